@@ -7,12 +7,12 @@ import (
 	"time"
 
 	"github.com/anilkonac/magrix/asset"
+	"github.com/anilkonac/magrix/world"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/yohamta/ganim8/v2"
 )
 
 const (
-	durationEnemy1IdleMs    = 150
 	durationPlayerIdleMs    = 200
 	durationPlayerWalkingMs = 75
 	durationExplosionMs     = 50
@@ -26,7 +26,6 @@ const (
 var (
 	animDeltaTime = time.Millisecond * time.Duration(math.Ceil(deltaTimeSec*1000))
 
-	animEnemy1Idle       *ganim8.Animation
 	animPlayerIdle       *ganim8.Animation
 	animPlayerWalk       *ganim8.Animation
 	animRocket           *ganim8.Animation
@@ -43,7 +42,6 @@ var (
 func init() {
 	animPlayerIdle = newAnim("1-4", 1, asset.Bytes(asset.AnimPlayerIdle), gridWidthPlayer, gridHeightPlayer, 64, 32, durationPlayerIdleMs)
 	animPlayerWalk = newAnim("1-8", 1, asset.Bytes(asset.AnimPlayerWalk), gridWidthPlayer, gridHeightPlayer, 128, 32, durationPlayerWalkingMs)
-	animEnemy1Idle = newAnim("1-4", 1, asset.Bytes(asset.AnimEnemy1Idle), gridWidthPlayer, gridHeightPlayer, 64, 32, durationEnemy1IdleMs)
 	animRocket = newAnim("1-2", 1, asset.Bytes(asset.AnimRocket), 16, 16, 32, 16, 50)
 	animExplosion = newAnim("1-14", 1, asset.Bytes(asset.AnimExplosion), 32, 32, 32*numFramesExplosion, 32, durationExplosionMs)
 	animElectricBlue = newAnim("1-3", 1, asset.Bytes(asset.AnimElectricBlue), 16, 16, 48, 16, durationElectricMs)
@@ -55,6 +53,32 @@ func init() {
 	spriteGun = *newSprite("1-3", 1, asset.Bytes(asset.SpriteGun), gridWidthGun, gridHeightGun, 3*gridWidthGun, gridHeightGun)
 }
 
+// Animations of the enemy archetypes by their names, made when an enemy of the archetype is first drawn
+var animEnemies = make(map[string]*ganim8.Animation)
+
+// enemyAnim returns the animation of the enemy archetype.
+func enemyAnim(archetype *world.EnemyArchetype) *ganim8.Animation {
+	if anim, ok := animEnemies[archetype.Name]; ok {
+		return anim
+	}
+
+	s := archetype.Sprite
+	image := assetImage(s.Image)
+	bounds := image.Bounds()
+	grid := ganim8.NewGrid(s.FrameWidth, s.FrameHeight, bounds.Dx(), bounds.Dy())
+	anim := ganim8.NewAnimation(ganim8.NewSprite(image, grid.GetFrames(s.Frames, 1)), time.Millisecond*time.Duration(s.FrameMs), ganim8.Nop)
+	animEnemies[archetype.Name] = anim
+	return anim
+}
+
+// assetImage decodes an embedded image.
+func assetImage(path string) *ebiten.Image {
+	img, err := png.Decode(bytes.NewReader(asset.Bytes(path)))
+	panicErr(err)
+
+	return ebiten.NewImageFromImage(img)
+}
+
 func newAnim(column string, row int, fileBytes []byte, gridWidth, gridHeight, imageWidth, imageHeight, frameDurationMs int) *ganim8.Animation {
 	spr := newSprite(column, row, fileBytes, gridWidth, gridHeight, imageWidth, imageHeight)
 	return ganim8.NewAnimation(spr, time.Millisecond*time.Duration(frameDurationMs), ganim8.Nop)
@@ -1,6 +1,6 @@
 <?xml version="1.0" encoding="UTF-8"?>
 <tileset version="1.8" tiledversion="1.8.5" name="0x72_16x16RobotTileset.v1" tilewidth="16" tileheight="16" tilecount="1024" columns="32">
- <image source="0x72_16x16RobotTileset.v1.png" width="512" height="512"/>
+ <image source="tileset.png" width="512" height="512"/>
  <tile id="482">
   <animation>
    <frame tileid="256" duration="200"/>
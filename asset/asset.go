@@ -1,14 +1,12 @@
 package asset
 
 import (
-	"bytes"
 	"embed"
-	"image/png"
 
-	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/lafriks/go-tiled"
 )
 
-//go:embed *.png sounds fonts gameMap.tmx
+//go:embed *.png *.tmx *.tsx *.json sounds fonts
 var fs embed.FS
 
 var (
@@ -26,17 +24,18 @@ var (
 	SpriteButton         = "theButton.png"
 	SpriteGun            = "sprite_gun.png"
 
-	ImageHeart               = "heart.png"
-	ImageArrow               = "arrow.png"
-	ImageMapLayerPlatforms   = "map_layer_platforms.png"
-	ImageMapLayerDecorations = "map_layer_decorations.png"
+	ImageHeart = "heart.png"
+	ImageArrow = "arrow.png"
 
 	FontMinecraft = "fonts/Minecraft.ttf"
 
 	Music          = "sounds/RaceToMars.ogg"
 	SoundExplosion = "sounds/explosion.wav"
 
-	Map = "gameMap.tmx"
+	DataEnemies = "enemies.json" // enemy archetypes
+
+	Map     = "gameMap.tmx"
+	MapTest = "testLevel.tmx"
 )
 
 func Bytes(path string) []byte {
@@ -46,11 +45,9 @@ func Bytes(path string) []byte {
 	return bytes
 }
 
-func Image(path string) *ebiten.Image {
-	img, err := png.Decode(bytes.NewReader(Bytes(path)))
-	panik(err)
-
-	return ebiten.NewImageFromImage(img)
+// LoadMap parses an embedded Tiled map. External tilesets are loaded from the embedded files too.
+func LoadMap(path string) (*tiled.Map, error) {
+	return tiled.LoadFile(path, tiled.WithFileSystem(fs))
 }
 
 func panik(err error) {
@@ -0,0 +1,146 @@
+// Copyright 2022 Anıl Konaç
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/anilkonac/magrix/asset"
+	"github.com/anilkonac/magrix/world"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/lafriks/go-tiled"
+)
+
+const levelCompleteSec = 3
+
+// Levels of the campaign in the order they are played
+var campaignLevels = []string{
+	asset.Map,
+	asset.MapTest,
+}
+
+// campaign keeps track of the level being played.
+type campaign struct {
+	levels   []string
+	iLevel   int
+	fromDisk bool                     // levels are loaded from the file system instead of the embedded assets
+	images   map[string]*ebiten.Image // tileset images shared between restarts
+	modTime  time.Time                // modification time of the level file when it was loaded
+	savePath string                   // the progress isn't saved if empty
+	progress *world.Progress          // progress in the current level, nil if no checkpoint is reached yet
+
+	// Results
+	elapsedSec    float64
+	enemiesKilled int
+}
+
+func newCampaign(levels []string) *campaign {
+	return &campaign{
+		levels: levels,
+		images: make(map[string]*ebiten.Image),
+	}
+}
+
+// newCampaignFromDisk creates a single level campaign from a map file on the file system.
+// Tilesets and images the map refers to are resolved relative to it.
+func newCampaignFromDisk(path string) *campaign {
+	c := newCampaign([]string{filepath.Clean(path)})
+	c.fromDisk = true
+	return c
+}
+
+// loadLevel parses the map of the current level.
+func (c *campaign) loadLevel() (*tiled.Map, error) {
+	path := c.levels[c.iLevel]
+	if !c.fromDisk {
+		return asset.LoadMap(path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	c.modTime = info.ModTime()
+
+	return tiled.LoadFile(path)
+}
+
+// loadImage loads the image at the path the current level refers to.
+func (c *campaign) loadImage(path string) (*ebiten.Image, error) {
+	if img, ok := c.images[path]; ok {
+		return img, nil
+	}
+
+	var img *ebiten.Image
+	if c.fromDisk {
+		var err error
+		img, _, err = ebitenutil.NewImageFromFile(path)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		img = assetImage(filepath.ToSlash(path))
+	}
+
+	c.images[path] = img
+	return img, nil
+}
+
+// levelModified reports whether the level file has changed on the disk since it was loaded.
+func (c *campaign) levelModified() bool {
+	if !c.fromDisk {
+		return false
+	}
+	info, err := os.Stat(c.levels[c.iLevel])
+	if err != nil {
+		// The file may be in the middle of being saved
+		return false
+	}
+	return info.ModTime().After(c.modTime)
+}
+
+// clearImages drops the cached images so that they are loaded again with the level.
+func (c *campaign) clearImages() {
+	c.images = make(map[string]*ebiten.Image)
+}
+
+// reset starts the campaign over from the first level.
+func (c *campaign) reset() {
+	c.iLevel = 0
+	c.elapsedSec = 0
+	c.enemiesKilled = 0
+	c.progress = nil
+	c.deleteSave()
+}
+
+// hasProgress reports whether the campaign is continued from where the player left it.
+func (c *campaign) hasProgress() bool {
+	return c.iLevel > 0 || c.progress != nil
+}
+
+// reachCheckpoint keeps the progress in the current level to respawn with it, and saves it.
+func (c *campaign) reachCheckpoint(progress world.Progress) {
+	c.progress = &progress
+	c.save()
+}
+
+func (c *campaign) isLastLevel() bool {
+	return c.iLevel == len(c.levels)-1
+}
+
+// advance moves to the next level and builds it with load. The campaign stays at the current level if load fails.
+func (c *campaign) advance(load func() error) error {
+	progress := c.progress
+	c.iLevel++
+	c.progress = nil
+	if err := load(); err != nil {
+		c.iLevel--
+		c.progress = progress
+		return err
+	}
+	c.save()
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2022 Anıl Konaç
+
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anilkonac/magrix/world"
+)
+
+func TestCampaignAdvance(t *testing.T) {
+	camp := newCampaign(campaignLevels)
+	camp.progress = &world.Progress{Checkpoint: 10, NumLives: 2}
+
+	// The level after can't be loaded
+	if err := camp.advance(func() error { return errors.New("broken map") }); err == nil {
+		t.Fatal("advance doesn't return the error of loading the level")
+	}
+	if camp.iLevel != 0 || camp.progress == nil || camp.progress.Checkpoint != 10 {
+		t.Fatalf("campaign at level %d with progress %v after failing to advance", camp.iLevel, camp.progress)
+	}
+
+	var loadedLevel int
+	if err := camp.advance(func() error { loadedLevel = camp.iLevel; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if camp.iLevel != 1 || loadedLevel != 1 || camp.progress != nil {
+		t.Fatalf("campaign at level %d with progress %v after advancing, level %d loaded", camp.iLevel, camp.progress, loadedLevel)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2022 Anıl Konaç
+
+package main
+
+import (
+	"image/color"
+
+	"github.com/anilkonac/magrix/world"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+var (
+	colorCrate       = color.RGBA{120, 124, 134, 255}
+	colorCrateBorder = color.RGBA{70, 72, 80, 255}
+	imageCrate       = ebiten.NewImage(tileLength, tileLength)
+)
+
+func init() {
+	imageCrate.Fill(colorCrateBorder)
+	ebitenutil.DrawRect(imageCrate, 2, 2, tileLength-4, tileLength-4, colorCrate)
+	ebitenutil.DrawLine(imageCrate, 2, 2, tileLength-2, tileLength-2, colorCrateBorder)
+
+	registerEntityDrawer(world.ObjectTypeCrate, func(e world.Entity) {
+		drawCrate(e.(*world.Crate))
+	})
+}
+
+func drawCrate(c *world.Crate) {
+	pos := c.Position()
+	var drawOptions ebiten.DrawImageOptions
+	drawOptions.GeoM.Translate(-tileLength/2.0, -tileLength/2.0)
+	drawOptions.GeoM.Scale(c.Width/tileLength, c.Height/tileLength)
+	drawOptions.GeoM.Rotate(c.Body.Angle())
+	drawOptions.GeoM.Translate(pos.X, pos.Y)
+	imageObjects.DrawImage(imageCrate, &drawOptions)
+}
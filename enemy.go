@@ -3,123 +3,88 @@
 package main
 
 import (
-	"math"
 	"math/rand"
 
-	"github.com/jakecoffman/cp"
+	"github.com/anilkonac/magrix/world"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/yohamta/ganim8/v2"
 )
 
 const (
-	enemyMass              = 0.75
-	enemyFriction          = 0.75
-	enemyMoment            = 125
-	enemyWidthTile         = 1
-	enemyHeightTile        = 1.5
-	enemyEyeRange          = mapWidth / 2.0
-	enemyEyeRadius         = mapHeight / 8.0
-	enemyAttackCooldownSec = 2.0
+	alertBarWidth   = tileLength
+	alertBarHeight  = 2
+	alertBarOffsetY = 4 // above the top of the enemy
 )
 
-type enemy struct {
-	size              cp.Vector
-	drawOptions       ganim8.DrawOptions
-	body              *cp.Body
-	shape             *cp.Shape
-	curAnim           ganim8.Animation
-	eyeRay            [2]cp.Vector
-	attackCooldownSec float32
-	turnedLeft        bool
-	isAlive           bool
-	drawActive        bool
+// enemySprite draws an enemy of the world.
+type enemySprite struct {
+	enemy       *world.Enemy
+	drawOptions ganim8.DrawOptions
+	curAnim     ganim8.Animation
 }
 
-func newEnemy(pos cp.Vector, space *cp.Space, turnedLeft bool) *enemy {
-	enemy := &enemy{
-		size: cp.Vector{
-			X: enemyWidthTile * tileLength,
-			Y: enemyHeightTile * tileLength},
+func newEnemySprite(e *world.Enemy) *enemySprite {
+	s := e.Archetype.Sprite
+	sprite := &enemySprite{
+		enemy: e,
 		drawOptions: ganim8.DrawOptions{
-			OriginX: 0.5,
-			OriginY: 0.64,
-			ScaleX:  1.0,
-			ScaleY:  1.0,
+			OriginX: s.OriginX,
+			OriginY: s.OriginY,
+			ScaleX:  s.Scale,
+			ScaleY:  s.Scale,
 		},
-		curAnim:           *animEnemy1Idle,
-		attackCooldownSec: 0,
-		turnedLeft:        turnedLeft,
-		isAlive:           true,
-		drawActive:        true,
+		curAnim: *enemyAnim(e.Archetype),
 	}
-	if turnedLeft {
-		enemy.drawOptions.ScaleX = -1.0
-	}
-
-	enemy.curAnim.GoToFrame(1 + rand.Intn(4)) // Have all enemies start at different frames
-
-	body := cp.NewBody(enemyMass, enemyMoment)
-	body.SetPosition(cp.Vector{X: pos.X, Y: pos.Y})
-	body.SetVelocityUpdateFunc(enemyUpdateVelocity)
-	enemy.body = body
-
-	enemy.shape = cp.NewBox(enemy.body, enemy.size.X, enemy.size.Y, 0)
-	enemy.shape.SetElasticity(playerElasticity)
-	enemy.shape.SetFriction(enemyFriction)
+	sprite.drawOptions.ColorM.Scale(s.Color[0], s.Color[1], s.Color[2], s.Color[3])
 
-	space.AddBody(enemy.body)
-	space.AddShape(enemy.shape)
+	sprite.curAnim.GoToFrame(1 + rand.Intn(4)) // Have all enemies start at different frames
+	sprite.updateDrawOptions()
 
-	return enemy
+	return sprite
 }
 
-func (e *enemy) update(force *cp.Vector) (hasFallen bool) {
-	pos := e.body.Position()
-
-	if force != nil {
-		e.body.SetForce(*force)
+func (s *enemySprite) update() {
+	if s.enemy.IsAlive {
+		s.curAnim.Update(animDeltaTime)
 	}
+	s.updateDrawOptions()
+}
 
-	if e.isAlive {
-		e.body.EachArbiter(func(a *cp.Arbiter) {
-			velSq := e.body.Velocity().LengthSq()
-			// fmt.Printf("vel: %v\n", velSq)
-			if a.IsFirstContact() && velSq > 50000 {
-				hasFallen = true
-				e.isAlive = false
-			}
-		})
-
-		// Raycast
-		angle := e.body.Angle()
-		turnMult := 1.0
-		if e.turnedLeft {
-			turnMult = -1.0
-		}
-		e.eyeRay[0] = pos
-		e.eyeRay[1] = e.eyeRay[0].Add(
-			cp.Vector{
-				X: enemyEyeRange * turnMult * math.Cos(angle), Y: enemyEyeRange * math.Sin(angle),
-			},
-		)
-
-		e.curAnim.Update(animDeltaTime)
+func (s *enemySprite) updateDrawOptions() {
+	pos := s.enemy.Body.Position()
+	s.drawOptions.X = pos.X
+	s.drawOptions.Y = pos.Y
+	s.drawOptions.Rotate = s.enemy.Body.Angle()
+	if s.enemy.Archetype.Round {
+		// Roll along the ground
+		s.drawOptions.Rotate = pos.X / (s.enemy.Archetype.Width * tileLength / 2.0)
+	}
+	s.drawOptions.ScaleX = s.enemy.Archetype.Sprite.Scale
+	if s.enemy.TurnedLeft {
+		s.drawOptions.ScaleX = -s.enemy.Archetype.Sprite.Scale
 	}
-
-	// Update draw options
-	e.drawOptions.X = pos.X
-	e.drawOptions.Y = pos.Y
-	e.drawOptions.Rotate = e.body.Angle()
-
-	return hasFallen
 }
 
-func (e *enemy) draw() {
-	if !e.drawActive {
+func (s *enemySprite) draw() {
+	if s.enemy.Removed {
 		return
 	}
-	e.curAnim.Draw(imageObjects, &e.drawOptions)
+	s.curAnim.Draw(imageObjects, &s.drawOptions)
+	s.drawAlertness()
 }
 
-func enemyUpdateVelocity(body *cp.Body, gravity cp.Vector, damping, dt float64) {
-	body.UpdateVelocity(gravity, damping, dt)
+// drawAlertness draws a bar above the enemy filling up as it notices the player, red once it has.
+func (s *enemySprite) drawAlertness() {
+	if !s.enemy.IsAlive || s.enemy.Alertness <= 0 {
+		return
+	}
+	pos := s.enemy.Body.Position()
+	x := pos.X - alertBarWidth/2.0
+	y := pos.Y - s.enemy.Archetype.Height*tileLength/2.0 - alertBarOffsetY - alertBarHeight
+	clr := colorOrange
+	if s.enemy.Alertness >= 1 {
+		clr = colorEnemy
+	}
+	ebitenutil.DrawRect(imageObjects, x, y, alertBarWidth, alertBarHeight, colorBackground)
+	ebitenutil.DrawRect(imageObjects, x, y, alertBarWidth*s.enemy.Alertness, alertBarHeight, clr)
 }
@@ -0,0 +1,22 @@
+// Copyright 2022 Anıl Konaç
+
+package main
+
+import "github.com/anilkonac/magrix/world"
+
+// entityDrawers draw the entities of the world by their types.
+// Entities of types without a drawer are not drawn.
+var entityDrawers = make(map[string]func(e world.Entity))
+
+// registerEntityDrawer makes the entities of the type be drawn by draw.
+func registerEntityDrawer(typ string, draw func(e world.Entity)) {
+	entityDrawers[typ] = draw
+}
+
+func drawEntities(entities []world.Entity) {
+	for _, entity := range entities {
+		if draw, ok := entityDrawers[entity.Type()]; ok {
+			draw(entity)
+		}
+	}
+}
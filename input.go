@@ -1,32 +1,28 @@
 package main
 
 import (
+	"github.com/anilkonac/magrix/world"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/jakecoffman/cp"
 )
 
-type gunInput uint8
-
-const (
-	gunInputNone gunInput = iota
-	gunInputAttract
-	gunInputRepel
-)
-
 type input struct {
-	cursorPos cp.Vector
-	up/*, down*/ bool
-	left, right bool
-
-	gun gunInput
+	world.Input // controls of the player
 
-	activate bool
+	cursorPos cp.Vector
 
 	escape           bool
 	pausePlay        bool
 	wheelDx, wheelDy float64
 	musicToggle      bool
+	reload           bool
+
+	// Menus
+	confirm      bool
+	restartLevel bool
+	quit         bool
+	newGame      bool
 }
 
 func (i *input) update() {
@@ -35,9 +31,9 @@ func (i *input) update() {
 	i.cursorPos = cp.Vector{X: float64(x), Y: float64(y)}
 
 	// Update movement key states
-	i.right = ebiten.IsKeyPressed(ebiten.KeyD) || ebiten.IsKeyPressed(ebiten.KeyRight)
-	i.left = ebiten.IsKeyPressed(ebiten.KeyA) || ebiten.IsKeyPressed(ebiten.KeyLeft)
-	i.up = ebiten.IsKeyPressed(ebiten.KeyW) || ebiten.IsKeyPressed(ebiten.KeyUp) || ebiten.IsKeyPressed(ebiten.KeySpace)
+	i.Right = ebiten.IsKeyPressed(ebiten.KeyD) || ebiten.IsKeyPressed(ebiten.KeyRight)
+	i.Left = ebiten.IsKeyPressed(ebiten.KeyA) || ebiten.IsKeyPressed(ebiten.KeyLeft)
+	i.Up = ebiten.IsKeyPressed(ebiten.KeyW) || ebiten.IsKeyPressed(ebiten.KeyUp) || ebiten.IsKeyPressed(ebiten.KeySpace)
 	// i.down = ebiten.IsKeyPressed(ebiten.KeyA) || ebiten.IsKeyPressed(ebiten.KeyDown) || ebiten.IsKeyPressed(ebiten.KeyControlLeft)
 
 	pressedMouseLeft := ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
@@ -45,20 +41,31 @@ func (i *input) update() {
 
 	// Update mouse press actions
 	if pressedMouseLeft && pressedMouseRight {
-		i.gun = gunInputNone
+		i.Gun = world.GunInputNone
 	} else if pressedMouseLeft {
-		i.gun = gunInputRepel
+		i.Gun = world.GunInputRepel
 	} else if pressedMouseRight {
-		i.gun = gunInputAttract
+		i.Gun = world.GunInputAttract
 	} else {
-		i.gun = gunInputNone
+		i.Gun = world.GunInputNone
 	}
 
-	i.activate = inpututil.IsKeyJustPressed(ebiten.KeyE)
+	i.Activate = inpututil.IsKeyJustPressed(ebiten.KeyE)
 
 	i.escape = ebiten.IsKeyPressed(ebiten.KeyEscape)
 	i.pausePlay = inpututil.IsKeyJustPressed(ebiten.KeyP) || inpututil.IsKeyJustPressed(ebiten.KeyPause)
 	i.musicToggle = inpututil.IsKeyJustPressed(ebiten.KeyM)
+	i.reload = inpututil.IsKeyJustPressed(ebiten.KeyF5)
+
+	i.confirm = inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter)
+	i.restartLevel = inpututil.IsKeyJustPressed(ebiten.KeyR)
+	i.quit = inpututil.IsKeyJustPressed(ebiten.KeyQ)
+	i.newGame = inpututil.IsKeyJustPressed(ebiten.KeyN)
 
 	i.wheelDx, i.wheelDy = ebiten.Wheel()
 }
+
+// clearControls drops the inputs that control the player. The gun keeps aiming at the cursor.
+func (i *input) clearControls() {
+	i.Input = world.Input{Aim: i.Aim}
+}
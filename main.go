@@ -3,16 +3,16 @@
 package main
 
 import (
-	"bytes"
+	"flag"
 	"fmt"
 	"image/color"
 	"log"
 	"math"
-	"time"
 
 	_ "embed"
 
 	"github.com/anilkonac/magrix/asset"
+	"github.com/anilkonac/magrix/world"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/jakecoffman/cp"
@@ -23,26 +23,23 @@ import (
 const (
 	screenWidth    = 960
 	screenHeight   = 720
-	deltaTimeSec   = 1.0 / 60.0
-	mapWidth       = 960
-	mapHeight      = 960
-	tileLength     = 16
+	deltaTimeSec   = world.DeltaTimeSec
+	mapWidth       = world.MapWidth
+	mapHeight      = world.MapHeight
+	tileLength     = world.TileLength
 	restartTimeSec = 3
+	levelWatchSec  = 1
 )
 
 const (
 	crosshairRadius      = 6
 	crosshairInnerRadius = 2
 	rayHitImageWidth     = 4
-	wallElasticity       = 0
-	wallFriction         = 1
-	// spaceIterations      = 10
 )
 
 const (
-	zoomMultiplier        = 0.1
-	uiArrowDistance       = screenHeight/2.0 - 50
-	interactionRadiusTile = 1.25
+	zoomMultiplier  = 0.1
+	uiArrowDistance = screenHeight/2.0 - 50
 )
 
 var (
@@ -70,11 +67,7 @@ var (
 	drawOptionsArrowOrange ebiten.DrawImageOptions
 )
 
-var (
-	imagePlatforms   *ebiten.Image
-	imageDecorations *ebiten.Image
-	imageObjects     *ebiten.Image = ebiten.NewImage(mapWidth, mapHeight)
-)
+var imageObjects = ebiten.NewImage(mapWidth, mapHeight)
 
 var (
 	cam              = camera.NewCamera(screenWidth, screenHeight, 0, 0, 0, 1)
@@ -85,8 +78,6 @@ var (
 )
 
 var (
-	gamePaused      bool
-	gameOver        bool
 	showArrowBlue   bool
 	showArrowOrange bool
 )
@@ -100,7 +91,7 @@ func panicErr(err error) {
 func init() {
 	initCursorImage()
 	initRayHitImage()
-	imageArrow = asset.Image(asset.ImageArrow)
+	imageArrow = assetImage(asset.ImageArrow)
 
 	// init color matrices
 	drawOptionsArrowBlue.ColorM.ScaleWithColor(colorBlue)
@@ -135,112 +126,130 @@ func initRayHitImage() {
 }
 
 // game implements ebiten.game interface.
+// The level itself is simulated by the world, the game draws it and plays its sounds.
 type game struct {
-	player         player
-	enemies        []*enemy
-	walls          []*cp.Shape
-	space          *cp.Space
-	input          input
-	inputGunPrev   gunInput
-	rayHitInfo     cp.SegmentQueryInfo
-	rocketManager  rocketManager
-	terminalBlue   *terminal
-	terminalOrange *terminal
-	terminalIntro  *terminal
-	eWallBlue      *electricWall
-	eWallOrange    *electricWall
-	button         *button
-	gameOverTimer  float32
+	world            *world.World
+	input            input
+	inputGunPrev     world.GunInput
+	player           *playerSprite
+	enemies          []*enemySprite
+	platforms        []*platformSprite
+	explosions       []*explosion
+	campaign         *campaign
+	states           []gameState
+	layerPlatforms   *tileLayer
+	layerDecorations *tileLayer
+	levelWatchTimer  float32
+	recorder         *replayRecorder
+	replay           *replayPlayer
 }
 
-func newGame() *game {
-	game := new(game)
-	game.restart()
+func newGame(camp *campaign) *game {
+	game := &game{
+		campaign: camp,
+	}
+	panicErr(game.loadLevel())
+	game.setState(&titleState{})
 
 	return game
 }
 
-func (g *game) restart() {
-	space := cp.NewSpace()
-	// space.Iterations = spaceIterations
-	space.SetGravity(cp.Vector{X: 0, Y: gravity})
-
-	*g = game{
-		space: space,
-		rocketManager: rocketManager{
-			space: space,
-		},
+// restart builds the current level again. Errors are logged and returned, and the current level goes on, so the
+// state shouldn't change when it fails.
+func (g *game) restart() error {
+	err := g.loadLevel()
+	if err != nil {
+		log.Printf("Level could not be restarted: %v", err)
 	}
-
-	// Parse map file
-	gameMap, err := tiled.LoadReader("", bytes.NewReader(asset.Bytes(asset.Map)))
-	panicErr(err)
-	g.loadMap(gameMap)
-
-	cam.Zoom(zoom)
-	gameOver = false
-	showArrowBlue = false
-	showArrowOrange = false
+	return err
 }
 
-func (g *game) loadMap(gameMap *tiled.Map) {
-	const (
-		objectGroupWalls         = 0
-		objectGroupPlayer        = 1
-		objectGroupEnemy         = 2
-		objectGroupWallsElectric = 3
-		objectGroupTerminals     = 4
-		objectGroupButton        = 5
-	)
+// reload rebuilds the current level from its source while keeping the player, and so the camera, where it was, and its
+// lives. Errors are only logged so that a broken map can be fixed without restarting the game.
+func (g *game) reload() {
+	pos := g.world.Player.Body.Position()
+	vel := g.world.Player.Body.Velocity()
+	numLives := g.world.Player.NumLives
 
-	g.addWalls(gameMap.ObjectGroups[objectGroupWalls].Objects)
-
-	// Add Electric Walls (Manual assignment for now)
-	g.eWallBlue = newElectricWall(gameMap.ObjectGroups[objectGroupWallsElectric].Objects[0], g.space)
-	g.eWallOrange = newElectricWall(gameMap.ObjectGroups[objectGroupWallsElectric].Objects[1], g.space)
-
-	// Add terminals
-	g.terminalIntro = newTerminal(gameMap.ObjectGroups[objectGroupTerminals].Objects[2], g.space)
-	g.terminalIntro.spr = spriteTerminalGreen
-	g.terminalBlue = newTerminal(gameMap.ObjectGroups[objectGroupTerminals].Objects[0], g.space)
-	g.terminalOrange = newTerminal(gameMap.ObjectGroups[objectGroupTerminals].Objects[1], g.space)
+	g.campaign.clearImages()
+	if err := g.loadLevel(); err != nil {
+		log.Printf("Level could not be reloaded: %v", err)
+		return
+	}
 
-	var playerStartLoc cp.Vector
-	playerStartLoc.X = gameMap.ObjectGroups[objectGroupPlayer].Objects[0].X
-	playerStartLoc.Y = gameMap.ObjectGroups[objectGroupPlayer].Objects[0].Y
-	g.player = *newPlayer(playerStartLoc, g.space)
+	g.world.Player.Body.SetPosition(pos)
+	g.world.Player.Body.SetVelocityVector(vel)
+	g.world.Player.Pos = pos
+	g.world.Player.NumLives = numLives
+	cam.SetPosition(pos.X, pos.Y)
+}
 
-	// Add enemies
-	for _, enemyPos := range gameMap.ObjectGroups[objectGroupEnemy].Objects {
-		g.enemies = append(g.enemies, newEnemy(cp.Vector{X: enemyPos.X, Y: enemyPos.Y}, g.space, enemyPos.Properties.GetBool("turnedLeft")))
+// loadLevel builds the current level of the campaign from scratch.
+// The game is left untouched if the level can't be loaded.
+func (g *game) loadLevel() error {
+	// Parse the map of the current level
+	gameMap, err := g.campaign.loadLevel()
+	if err != nil {
+		return err
+	}
 
+	w, err := world.New(gameMap)
+	if err != nil {
+		return err
+	}
+	if g.campaign.progress != nil {
+		w.Restore(*g.campaign.progress)
 	}
 
-	// Add the button
-	g.button = newButton(gameMap.ObjectGroups[objectGroupButton].Objects[0], g.space)
+	level := game{
+		world:    w,
+		player:   newPlayerSprite(w.Player),
+		campaign: g.campaign,
+		states:   g.states,
+		recorder: g.recorder,
+		replay:   g.replay,
+	}
+	for _, enemy := range w.Enemies {
+		level.enemies = append(level.enemies, newEnemySprite(enemy))
+	}
+	if err := level.loadLayers(gameMap); err != nil {
+		return err
+	}
+	for _, entity := range w.Entities {
+		if platform, ok := entity.(*world.Platform); ok {
+			level.platforms = append(level.platforms, newPlatformSprite(platform, level.layerPlatforms))
+		}
+	}
+	*g = level
 
-	// Load layer images
-	imagePlatforms = asset.Image(asset.ImageMapLayerPlatforms)
-	imageDecorations = asset.Image(asset.ImageMapLayerDecorations)
+	cam.Zoom(zoom)
+	showArrowBlue = false
+	showArrowOrange = false
 
+	return nil
 }
 
-func (g *game) addWalls(wallObjects []*tiled.Object) {
-	for _, obj := range wallObjects {
-		radius := math.Min(obj.Width, obj.Height) / 2.0
-		x2 := obj.X + obj.Width - radius
-		y2 := obj.Y + obj.Height - radius
-		shape := g.space.AddShape(cp.NewSegment(g.space.StaticBody, cp.Vector{X: obj.X + radius, Y: obj.Y + radius}, cp.Vector{X: x2, Y: y2}, radius))
-		shape.SetElasticity(wallElasticity)
-		shape.SetFriction(wallFriction)
-
-		g.walls = append(g.walls, shape)
+// loadLayers renders the tile layers of the map.
+func (g *game) loadLayers(gameMap *tiled.Map) error {
+	platforms, err := mapLayer(gameMap, layerNamePlatforms)
+	if err != nil {
+		return err
 	}
+	g.layerPlatforms, err = newTileLayer(gameMap, g.campaign.loadImage, platforms)
+	if err != nil {
+		return err
+	}
+	g.layerDecorations, err = newTileLayer(gameMap, g.campaign.loadImage, backgroundLayers(gameMap)...)
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
 
 // Update is called every tick (1/60 [s] by default).
 func (g *game) Update() error {
-	g.input.update()
+	replaying := g.readInput()
 	if g.input.wheelDy > 0 {
 		zoom += zoomMultiplier
 	} else if g.input.wheelDy < 0 {
@@ -248,89 +257,105 @@ func (g *game) Update() error {
 	}
 	zoom = cp.Clamp(zoom, zoomMin, zoomMax)
 	cam.SetZoom(zoom)
-	cursorX, cursorY = cam.GetCursorCoords()
+	if replaying {
+		cursorX, cursorY = g.input.Aim.X, g.input.Aim.Y
+	} else {
+		cursorX, cursorY = cam.GetCursorCoords()
+		g.input.Aim = cp.Vector{X: cursorX, Y: cursorY}
+	}
 	drawOptionsCrosshair.GeoM.Reset()
 	cam.GetTranslation(&drawOptionsCrosshair, cursorX-crosshairRadius, cursorY-crosshairRadius)
 
 	g.updateSettings()
 
-	if gamePaused {
-		return nil
+	// Reload the level when asked to or when its file is edited.
+	// A replay reloads it on the ticks it was reloaded while recording.
+	g.levelWatchTimer += deltaTimeSec
+	if !replaying && g.levelWatchTimer >= levelWatchSec {
+		g.levelWatchTimer = 0
+		g.input.reload = g.input.reload || g.campaign.levelModified()
 	}
 
-	g.space.Step(deltaTimeSec)
-
-	g.rayCast()
-	hitBodies := g.rocketManager.update()
-	for _, hitBody := range hitBodies {
-		if hitBody == g.player.body {
-			g.player.hit()
-			if g.player.numLives <= 0 {
-				gameOver = true
-			}
-		} else {
-			for _, enemy := range g.enemies {
-				if hitBody == enemy.body && enemy.isAlive {
-					enemy.isAlive = false
-					g.killEnemy(enemy)
-				}
-			}
+	if g.recorder != nil {
+		if err := g.recorder.record(&g.input); err != nil {
+			log.Printf("Recording stopped: %v", err)
+			g.stopRecording()
 		}
 	}
 
-	if gameOver {
-		g.gameOverTimer += deltaTimeSec
-		if g.gameOverTimer >= restartTimeSec {
-			g.restart()
-			gameOver = false
-			return nil
-		}
+	if g.input.reload {
+		g.reload()
+		return nil
 	}
 
-	// Update player and player's gun
-	g.player.update(&g.input, &g.rayHitInfo)
-	cam.SetPosition(g.player.pos.X, g.player.pos.Y)
+	g.state().update(g)
 
-	// Send the negative of the player's gun force to the enemy
-	var force cp.Vector
-	var enemyFell bool
-	for _, enemy := range g.enemies {
-		if g.rayHitInfo.Shape == enemy.shape {
-			force = g.player.gunForce.Neg()
-			enemyFell = enemy.update(&force)
-		} else {
-			enemyFell = enemy.update(nil)
-		}
+	return nil
+}
 
-		if enemyFell {
-			g.killEnemy(enemy)
+// readInput reads the input of the tick from the replay if one is being played, otherwise from the devices.
+// It reports whether the input comes from the replay.
+func (g *game) readInput() (replaying bool) {
+	if g.replay != nil {
+		var err error
+		replaying, err = g.replay.next(&g.input)
+		if err != nil {
+			log.Printf("Replay stopped: %v", err)
 		}
-	}
-	// Send the negative of the player's gun force to the rocket
-	if g.input.gun != gunInputNone {
-		for _, rocket := range g.rocketManager.rockets {
-			if g.rayHitInfo.Shape == rocket.shape {
-				force = g.player.gunForce.Neg()
-				rocket.body.SetForce(force)
-			}
+		if replaying {
+			return true
 		}
+
+		// Hand the control over to the player
+		g.replay.close()
+		g.replay = nil
+		log.Print("Replay finished")
 	}
 
-	g.checkPlayerInteraction()
+	g.input.update()
+	return false
+}
 
-	// Update ewall animations
-	if g.eWallBlue != nil {
-		g.eWallBlue.update()
+func (g *game) stopRecording() {
+	if g.recorder == nil {
+		return
+	}
+	if err := g.recorder.close(); err != nil {
+		log.Print(err)
 	}
-	if g.eWallOrange != nil {
-		g.eWallOrange.update()
+	g.recorder = nil
+}
+
+// step advances the level by one tick.
+func (g *game) step() {
+	g.world.Step(&g.input.Input)
+	for _, event := range g.world.Events {
+		switch event.Kind {
+		case world.EventEnemyKilled:
+			g.campaign.enemiesKilled++
+		case world.EventCheckpoint:
+			g.campaign.reachCheckpoint(g.world.Progress())
+		}
 	}
 
-	g.updateDrawOptions()
+	cam.SetPosition(g.world.Player.Pos.X, g.world.Player.Pos.Y)
+	g.player.update()
+	for _, enemy := range g.enemies {
+		enemy.update()
+	}
+	g.updateExplosions()
 
-	g.inputGunPrev = g.input.gun
+	// Update tile animations
+	g.layerDecorations.update()
+	g.layerPlatforms.update()
 
-	return nil
+	// Update ewall animations
+	animElectricBlue.Update(animDeltaTime)
+	animElectricOrange.Update(animDeltaTime)
+
+	g.updateDrawOptions()
+
+	g.inputGunPrev = g.input.Gun
 }
 
 func (g *game) updateDrawOptions() {
@@ -341,265 +366,90 @@ func (g *game) updateDrawOptions() {
 
 	// Update ray hit image's draw options
 	drawOptionsRayHit.GeoM.Reset()
-	cam.GetTranslation(&drawOptionsRayHit, g.rayHitInfo.Point.X-rayHitImageRadius, g.rayHitInfo.Point.Y-rayHitImageRadius)
+	rayHitPoint := g.world.RayHitInfo.Point
+	cam.GetTranslation(&drawOptionsRayHit, rayHitPoint.X-rayHitImageRadius, rayHitPoint.Y-rayHitImageRadius)
 
-	if g.input.gun != g.inputGunPrev {
+	if g.input.Gun != g.inputGunPrev {
 		drawOptionsRayHit.ColorM.Reset()
-		if g.input.gun == gunInputAttract {
+		if g.input.Gun == world.GunInputAttract {
 			drawOptionsRayHit.ColorM.ScaleWithColor(colorGunAttract)
-		} else if g.input.gun == gunInputRepel {
+		} else if g.input.Gun == world.GunInputRepel {
 			drawOptionsRayHit.ColorM.ScaleWithColor(colorGunRepel)
 		} else {
 			drawOptionsRayHit.ColorM.ScaleWithColor(colorOrange)
 		}
 	}
 
-	// Blue arrow
-	direction := g.terminalBlue.pos.Sub(g.player.pos)
-	distanceSq := direction.LengthSq()
-	if distanceSq < 10*screenWidth {
-		showArrowBlue = false
-	} else if g.terminalIntro.triggered && !g.terminalBlue.triggered {
-		showArrowBlue = true
-		dirAngle := math.Atan2(direction.Y, direction.X)
-		drawOptionsArrowBlue.GeoM.Reset()
-		drawOptionsArrowBlue.GeoM.Scale(2.0, 2.0)
-		drawOptionsArrowBlue.GeoM.Rotate(dirAngle)
-		drawOptionsArrowBlue.GeoM.Translate(
-			screenWidth/2.0+uiArrowDistance*math.Cos(dirAngle),
-			screenHeight/2.0+uiArrowDistance*math.Sin(dirAngle),
-		)
+	// Arrows pointing to the terminals
+	terminalIntro := findTerminal(g.world.Entities, world.ColorNameGreen)
+	g.updateArrow(terminalIntro, findTerminal(g.world.Entities, world.ColorNameBlue), &showArrowBlue, &drawOptionsArrowBlue)
+	g.updateArrow(terminalIntro, findTerminal(g.world.Entities, world.ColorNameOrange), &showArrowOrange, &drawOptionsArrowOrange)
+}
+
+func (g *game) updateArrow(terminalIntro, target *world.Terminal, show *bool, drawOpts *ebiten.DrawImageOptions) {
+	if target == nil || terminalIntro == nil {
+		*show = false
+		return
 	}
 
-	// Orange arrow
-	direction = g.terminalOrange.pos.Sub(g.player.pos)
-	distanceSq = direction.LengthSq()
+	direction := target.Position().Sub(g.world.Player.Pos)
+	distanceSq := direction.LengthSq()
 	if distanceSq < 10*screenWidth {
-		showArrowOrange = false
-	} else if g.terminalIntro.triggered && !g.terminalOrange.triggered {
-		showArrowOrange = true
+		*show = false
+	} else if terminalIntro.Triggered && !target.Working && !target.Triggered {
+		*show = true
 		dirAngle := math.Atan2(direction.Y, direction.X)
-		drawOptionsArrowOrange.GeoM.Reset()
-		drawOptionsArrowOrange.GeoM.Scale(2.0, 2.0)
-		drawOptionsArrowOrange.GeoM.Rotate(dirAngle)
-		drawOptionsArrowOrange.GeoM.Translate(
+		drawOpts.GeoM.Reset()
+		drawOpts.GeoM.Scale(2.0, 2.0)
+		drawOpts.GeoM.Rotate(dirAngle)
+		drawOpts.GeoM.Translate(
 			screenWidth/2.0+uiArrowDistance*math.Cos(dirAngle),
 			screenHeight/2.0+uiArrowDistance*math.Sin(dirAngle),
 		)
 	}
 }
 
-// Goroutine
-func (g *game) killEnemy(e *enemy) {
-	e.isAlive = false
-
-	go func() {
-		ticker := time.NewTicker(time.Second * 2.0)
-		<-ticker.C
-		g.rocketManager.explosions = append(g.rocketManager.explosions, newExplosion(e.body.Position()))
-		<-ticker.C
-
-		// Delete the enemy
-		// ----------------
-		g.space.RemoveShape(e.shape)
-		g.space.RemoveBody(e.body)
-
-		// copy(g.enemies[iEnemy:], g.enemies[iEnemy+1:])
-		// g.enemies[len(g.enemies)-1] = nil
-		// g.enemies = g.enemies[:len(g.enemies)-1]
-		// --
-
-		e.drawActive = false
-
-		ticker.Stop()
-	}()
-}
-
-func (g *game) checkPlayerInteraction() {
-	if !g.input.activate {
-		return
-	}
-
-	interactionRadius := float64(interactionRadiusTile * tileLength)
-	// Check if near intro terminal
-	if g.terminalIntro.pos.Distance(g.player.pos) < interactionRadius {
-		showTextIntro = true
-
-		go func() {
-			duration := time.Duration(1000 * durationTextIntroSec)
-			timer := time.NewTimer(time.Millisecond * duration)
-			<-timer.C
-			showTextIntro = false
-			showArrowBlue = true
-			showArrowOrange = true
-			g.terminalIntro.trigger()
-		}()
-	}
-
-	// Check if near blue terminal
-	if !g.terminalBlue.triggered && (g.terminalBlue.pos.Distance(g.player.pos) < interactionRadius) {
-		g.terminalBlue.trigger()
-
-		showTextTerminalBlue = true
-		go func() {
-			timer := time.NewTimer(time.Second * durationTextTerminals)
-			<-timer.C
-			showTextTerminalBlue = false
-			g.player.numLives++
-			g.player.prepareLivesIndicator()
-
-			// Remove wall
-			g.space.RemoveShape(g.eWallBlue.shape)
-			g.space.RemoveBody(g.eWallBlue.shape.Body())
-			g.eWallBlue = nil
-
-		}()
-
-	}
-
-	// Check if near orange terminal
-	if !g.terminalOrange.triggered && (g.terminalOrange.pos.Distance(g.player.pos) < interactionRadius) {
-		g.terminalOrange.trigger()
-
-		showTextTerminalOrange = true
-		go func() {
-			timer := time.NewTimer(time.Second * durationTextTerminals)
-			<-timer.C
-			showTextTerminalOrange = false
-			g.player.numLives++
-			g.player.prepareLivesIndicator()
-
-			// Remove wall
-			g.space.RemoveShape(g.eWallOrange.shape)
-			g.space.RemoveBody(g.eWallOrange.shape.Body())
-			g.eWallOrange = nil
-
-		}()
-
-	}
-
-	// Check if near the button
-	if !g.button.triggered && (g.button.pos.Distance(g.player.pos) < interactionRadius) {
-		g.button.trigger()
-		showTextButton = true
-	}
-}
-
 func (g *game) updateSettings() {
 	// Escape from cursor captured mode
 	if g.input.escape {
 		ebiten.SetCursorMode(ebiten.CursorModeHidden)
-	} else if (ebiten.CursorMode() == ebiten.CursorModeHidden) && (g.input.gun == gunInputRepel) {
+	} else if (ebiten.CursorMode() == ebiten.CursorModeHidden) && (g.input.Gun == world.GunInputRepel) {
 		ebiten.SetCursorMode(ebiten.CursorModeCaptured)
 	}
 
-	if g.input.pausePlay {
-		gamePaused = !gamePaused
-		if gamePaused && (musicState == musicOn) {
-			playerMusic.Pause()
-			musicState = musicPaused
-		} else if musicState == musicPaused {
-			playerMusic.Play()
-			musicState = musicOn
-		}
-	}
-
 	if g.input.musicToggle {
 		if musicState == musicOn {
 			musicState = musicMuted
 			playerMusic.Pause()
-		} else if (musicState == musicMuted) && !gamePaused {
+		} else if (musicState == musicMuted) && !g.isPaused() {
 			musicState = musicOn
 			playerMusic.Play()
 		}
 	}
 }
 
-func (g *game) rayCast() {
-	gunRay := g.player.gunRay
-	var info cp.SegmentQueryInfo
-	var success bool
-	g.rayHitInfo.Alpha = 1.5
-
-	// Check walls
-	for _, shape := range g.walls {
-		success = shape.SegmentQuery(gunRay[0], gunRay[1], 0, &info)
-		if success && info.Alpha < g.rayHitInfo.Alpha {
-			g.rayHitInfo = info
-		}
-	}
-
-	// Check enemy
-	for _, enemy := range g.enemies {
-		success = enemy.shape.SegmentQuery(gunRay[0], gunRay[1], 0, &info)
-		if success && info.Alpha < g.rayHitInfo.Alpha {
-			g.rayHitInfo = info
-		}
-	}
-
-	// Check rockets
-	for _, rocket := range g.rocketManager.rockets {
-		success = rocket.shape.SegmentQuery(gunRay[0], gunRay[1], 0, &info)
-		if success && info.Alpha < g.rayHitInfo.Alpha {
-			g.rayHitInfo = info
-		}
-	}
-
-	// Check player
-	// for enemy to detect player
-	for _, enemy := range g.enemies {
-		if !enemy.isAlive {
-			continue
-		}
-		success = g.player.shape.SegmentQuery(enemy.eyeRay[0], enemy.eyeRay[1], enemyEyeRadius, &info)
-		if success && enemy.attackCooldownSec <= 0 {
-			enemyPos := enemy.body.Position()
-			enemyAngle := enemy.body.Angle()
-			var rocketSpawnPos cp.Vector
-			var rocketAngle float64
-			if !enemy.turnedLeft {
-				rocketSpawnPosHypot := math.Hypot(rocketSpawnPosRelative.X, rocketSpawnPosRelative.Y)
-				angleSpawnPos := math.Atan2(rocketSpawnPosRelative.Y, rocketSpawnPosRelative.X)
-				newSpawnPosAngle := angleSpawnPos + enemyAngle
-				rocketSpawnPos = enemyPos.Add(cp.Vector{
-					X: rocketSpawnPosHypot * math.Cos(newSpawnPosAngle), Y: rocketSpawnPosHypot * math.Sin(newSpawnPosAngle),
-				})
-				rocketAngle = enemyAngle
-			} else {
-				rocketSpawnPosRelativeLeft := cp.Vector{X: -rocketSpawnPosRelative.X, Y: rocketSpawnPosRelative.Y}
-
-				rocketSpawnPosHypot := math.Hypot(rocketSpawnPosRelativeLeft.X, rocketSpawnPosRelativeLeft.Y)
-				angleSpawnPos := math.Atan2(rocketSpawnPosRelativeLeft.Y, rocketSpawnPosRelativeLeft.X)
-				newSpawnPosAngle := angleSpawnPos + enemyAngle
-				rocketSpawnPos = enemyPos.Add(cp.Vector{
-					X: rocketSpawnPosHypot * math.Cos(newSpawnPosAngle), Y: rocketSpawnPosHypot * math.Sin(newSpawnPosAngle),
-				})
-				rocketAngle = enemyAngle - math.Pi
-			}
-			g.rocketManager.rockets = append(g.rocketManager.rockets, newRocket(
-				rocketSpawnPos, rocketAngle, g.space))
-			enemy.attackCooldownSec = enemyAttackCooldownSec
-		} else {
-			enemy.attackCooldownSec -= deltaTimeSec
-		}
-		// fmt.Printf("success: %v\n", success)
+// Draw is called every frame (typically 1/60[s] for 60Hz display).
+func (g *game) Draw(screen *ebiten.Image) {
+	for _, state := range g.states {
+		state.draw(g, screen)
 	}
 
+	// Print fps
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("TPS: %.2f  FPS: %.2f", ebiten.ActualTPS(), ebiten.ActualFPS()), screenWidth-140, 0)
+	// ebitenutil.DebugPrintAt(screen, fmt.Sprintf("X: %.0f, Y: %.0f", g.input.cursorPos.X, g.input.cursorPos.Y), 0, 15)
 }
 
-// Draw is called every frame (typically 1/60[s] for 60Hz display).
-func (g *game) Draw(screen *ebiten.Image) {
+// drawLevel draws the level with its HUD.
+func (g *game) drawLevel(screen *ebiten.Image) {
 	// screen.Fill(colorBackground)
 	imageObjects.Clear()
 	cam.Surface.Fill(colorBackground)
 
 	// Draw decorations
-	cam.Surface.DrawImage(imageDecorations, &drawOptionsZero)
+	cam.Surface.DrawImage(g.layerDecorations.image, &drawOptionsZero)
 
-	// Draw terminals
-	g.terminalIntro.draw()
-	g.terminalBlue.draw()
-	g.terminalOrange.draw()
+	// Draw terminals, electric walls, the button and other entities
+	drawEntities(g.world.Entities)
 
 	// Draw enemies
 	for _, enemy := range g.enemies {
@@ -607,18 +457,7 @@ func (g *game) Draw(screen *ebiten.Image) {
 	}
 
 	// Draw rockets
-	g.rocketManager.draw()
-
-	// Draw electric walls
-	if g.eWallBlue != nil {
-		g.eWallBlue.draw()
-	}
-	if g.eWallOrange != nil {
-		g.eWallOrange.draw()
-	}
-
-	// Draw the button
-	g.button.draw()
+	g.drawRockets()
 
 	cam.Surface.DrawImage(imageObjects, &drawOptionsZero)
 
@@ -626,7 +465,10 @@ func (g *game) Draw(screen *ebiten.Image) {
 	g.player.draw()
 
 	// Draw walls and platforms
-	cam.Surface.DrawImage(imagePlatforms, &drawOptionsZero)
+	cam.Surface.DrawImage(g.layerPlatforms.image, &drawOptionsZero)
+	for _, platform := range g.platforms {
+		platform.draw()
+	}
 
 	// Draw crosshair
 	cam.Surface.DrawImage(imageCrosshair, &drawOptionsCrosshair)
@@ -636,24 +478,11 @@ func (g *game) Draw(screen *ebiten.Image) {
 
 	cam.Blit(screen)
 
-	if showTextIntro {
-		screen.DrawImage(imageTextIntro, &drawOptionsTextIntro)
-	}
-
-	if showTextTerminalBlue {
-		screen.DrawImage(imageTextTerminalBlue, &drawOptionsTextTerminalBlue)
-	}
-
-	if showTextTerminalOrange {
-		screen.DrawImage(imageTextTerminalOrange, &drawOptionsTextTerminalOrange)
-	}
-
-	if showTextButton {
-		screen.DrawImage(imageTextButton, &drawOptionsTextButton)
-	}
-
-	if gameOver {
-		screen.DrawImage(imageTextFail, &drawOptionsTextFail)
+	// Draw the texts of the working terminals
+	for _, entity := range g.world.Entities {
+		if terminal, ok := entity.(*world.Terminal); ok && terminal.Working {
+			drawTerminalText(screen, terminal)
+		}
 	}
 
 	if showArrowBlue {
@@ -664,12 +493,9 @@ func (g *game) Draw(screen *ebiten.Image) {
 		screen.DrawImage(imageArrow, &drawOptionsArrowOrange)
 	}
 
-	// Draw hearts
+	// Draw hearts and the health of the current life
 	screen.DrawImage(imageLives, &drawOptionsLives)
-
-	// Print fps
-	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("TPS: %.2f  FPS: %.2f", ebiten.ActualTPS(), ebiten.ActualFPS()), screenWidth-140, 0)
-	// ebitenutil.DebugPrintAt(screen, fmt.Sprintf("X: %.0f, Y: %.0f", g.input.cursorPos.X, g.input.cursorPos.Y), 0, 15)
+	g.player.drawHealth(screen)
 }
 
 // Layout takes the outside size (e.g., the window size) and returns the (logical) screen size.
@@ -680,13 +506,54 @@ func (g *game) Layout(outsideWidth, outsideHeight int) (int, int) {
 }
 
 func main() {
+	levelPath := flag.String("level", "", "play the Tiled map (.tmx) at the given path instead of the campaign")
+	recordPath := flag.String("record", "", "record the input of the run to the replay file at the given path")
+	replayPath := flag.String("replay", "", "play the replay file at the given path back, then hand the control over")
+	flag.Parse()
+	if *recordPath != "" && *replayPath != "" {
+		log.Fatal("-record and -replay can't be used together")
+	}
+
 	ebiten.SetWindowSize(screenWidth, screenHeight)
 	ebiten.SetWindowTitle("Magrix")
 	// ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	// ebiten.SetFPSMode(ebiten.FPSModeVsyncOffMaximum)
 	ebiten.SetCursorMode(ebiten.CursorModeCaptured)
 
-	if err := ebiten.RunGame(newGame()); err != nil {
+	camp := newCampaign(campaignLevels)
+	if *levelPath != "" {
+		camp = newCampaignFromDisk(*levelPath)
+	} else if *recordPath == "" && *replayPath == "" {
+		// Replays start from scratch, so the campaign is only saved when it is played normally
+		savePath, err := defaultSavePath()
+		if err != nil {
+			log.Printf("Progress won't be saved: %v", err)
+		}
+		camp.savePath = savePath
+		if err := camp.load(); err != nil {
+			log.Printf("Save file could not be loaded: %v", err)
+		}
+	}
+
+	game := newGame(camp)
+	if *recordPath != "" {
+		recorder, err := newReplayRecorder(*recordPath, camp)
+		if err != nil {
+			log.Fatal(err)
+		}
+		game.recorder = recorder
+	}
+	if *replayPath != "" {
+		replay, err := openReplay(*replayPath, camp)
+		if err != nil {
+			log.Fatal(err)
+		}
+		game.replay = replay
+	}
+
+	err := ebiten.RunGame(game)
+	game.stopRecording()
+	if err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,41 @@
+// Copyright 2022 Anıl Konaç
+
+package main
+
+import (
+	"image"
+
+	"github.com/anilkonac/magrix/world"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// platformSprite draws a moving platform with the tiles at its start in the platforms layer.
+type platformSprite struct {
+	platform *world.Platform
+	image    *ebiten.Image
+}
+
+// newPlatformSprite moves the tiles of the platform from the layer to the sprite.
+func newPlatformSprite(p *world.Platform, layer *tileLayer) *platformSprite {
+	area := image.Rect(
+		int(p.Start.X-p.Width/2.0), int(p.Start.Y-p.Height/2.0),
+		int(p.Start.X+p.Width/2.0), int(p.Start.Y+p.Height/2.0),
+	)
+	tiles := layer.image.SubImage(area).(*ebiten.Image)
+
+	img := ebiten.NewImage(area.Dx(), area.Dy())
+	img.DrawImage(tiles, nil)
+	tiles.Clear()
+
+	return &platformSprite{
+		platform: p,
+		image:    img,
+	}
+}
+
+func (s *platformSprite) draw() {
+	pos := s.platform.Position()
+	var drawOptions ebiten.DrawImageOptions
+	cam.GetTranslation(&drawOptions, pos.X-s.platform.Width/2.0, pos.Y-s.platform.Height/2.0)
+	cam.Surface.DrawImage(s.image, &drawOptions)
+}
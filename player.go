@@ -6,61 +6,22 @@ import (
 	"math"
 
 	"github.com/anilkonac/magrix/asset"
+	"github.com/anilkonac/magrix/world"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/jakecoffman/cp"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/yohamta/ganim8/v2"
 )
 
-type playerState uint8
+const halfPi = math.Pi / 2.0
 
-const (
-	stateIdle playerState = iota
-	stateWalking
-	stateFiring
-	stateJumping
-	stateTotal
-)
-
-const (
-	halfPi        = math.Pi / 2.0
-	turnTolerance = 5 * cp.RadianConst
-)
-
-const (
-	playerWidthTile  = 10.0 / 16.0
-	playerHeightTile = 1.6
-	gunWidthTile     = 1
-	gunHeightTile    = 1.0 / 3.0
-)
-
-const (
-	gravity          = 750.0
-	playerMass       = 0.75
-	playerElasticity = 0.0
-	// Taken from cp-examples/player and modified
-	playerFriction        = playerGroundAccel / (4 * gravity)
-	playerVelocity        = 150.0
-	playerGroundAccelTime = 0.05
-	playerGroundAccel     = playerVelocity / playerGroundAccelTime
-	jumpHeightTile        = 1.5
-	//
-)
-
-const (
-	gunRange     = mapWidth
-	gunForceMult = 15
-	gunForceMax  = 750
-	gunMinAlpha  = 1e-5 // required to prevent player pos to go NaN
-)
-
-const playerStartLives = 4
-
-type gunState uint8
+const gunHeightTile = 1.0 / 3.0
 
+// Health bar under the hearts
 const (
-	gunStateIdle gunState = iota
-	gunStateAttract
-	gunStateRepel
+	healthBarX      = 4
+	healthBarY      = 2.5*tileLength + 2
+	healthBarWidth  = 2.5 * 2 * tileLength
+	healthBarHeight = 6
 )
 
 var (
@@ -73,8 +34,6 @@ var (
 	drawOptionsLives ebiten.DrawImageOptions
 )
 
-var posGunRelative = cp.Vector{X: tileLength / 7.0, Y: -tileLength / 4.0}
-
 func init() {
 	// TODO: Draw gun images directly to the screen
 	drawOptionsGun := ganim8.DrawOptions{
@@ -85,263 +44,121 @@ func init() {
 	spriteGun.Draw(imageGunAttract, 1, &drawOptionsGun)
 	spriteGun.Draw(imageGunRepel, 2, &drawOptionsGun)
 
-	imageHeart = asset.Image(asset.ImageHeart)
+	imageHeart = assetImage(asset.ImageHeart)
 	imagePlayer = ebiten.NewImage(16, 32)
 	imageLives = ebiten.NewImage(tileLength*5, tileLength)
+
+	drawOptionsLives.GeoM.Scale(2.5, 2.5)
 }
 
-type player struct {
-	pos             cp.Vector
-	posGun          cp.Vector
-	size            cp.Vector
-	sizeGun         cp.Vector
-	angleGun        float64
-	shape           *cp.Shape
-	body            *cp.Body
+// playerSprite draws the player of the world with its gun and lives.
+type playerSprite struct {
+	player          *world.Player
+	sizeGun         float64
 	drawOptions     ebiten.DrawImageOptions
 	drawOptionsAnim ganim8.DrawOptions
 	drawOptionsGun  ebiten.DrawImageOptions
 	curAnim         *ganim8.Animation
-	onGround        bool
-	gunRay          [2]cp.Vector
-	gunForce        cp.Vector
-	state           playerState
-	stateGun        gunState
-	numLives        int
-	turnedLeft      bool
+	numLivesShown   int
 }
 
-func newPlayer(pos cp.Vector, space *cp.Space) *player {
-	player := &player{
-		pos: pos,
-		size: cp.Vector{
-			X: playerWidthTile * tileLength,
-			Y: playerHeightTile * tileLength,
-		},
-		sizeGun: cp.Vector{
-			X: gunWidthTile * tileLength,
-			Y: gunHeightTile * tileLength,
-		},
+func newPlayerSprite(p *world.Player) *playerSprite {
+	sprite := &playerSprite{
+		player:  p,
+		sizeGun: gunHeightTile * tileLength,
 		drawOptionsAnim: ganim8.DrawOptions{
 			OriginX: 0.0,
 			OriginY: 0.1,
 			ScaleX:  1.0,
 			ScaleY:  1.0,
 		},
-		state:    stateIdle,
-		curAnim:  animPlayerIdle,
-		numLives: playerStartLives,
+		curAnim: animPlayerIdle,
 	}
+	sprite.prepareLivesIndicator()
+	sprite.updateDrawOptions()
 
-	player.body = cp.NewBody(playerMass, cp.INFINITY)
-	player.body.SetPosition(cp.Vector{X: pos.X, Y: pos.Y})
-	player.body.SetVelocityUpdateFunc(playerUpdateVelocity)
-	player.shape = cp.NewBox(player.body, playerWidthTile*tileLength, playerHeightTile*tileLength, 0)
-	player.shape.SetElasticity(playerElasticity)
-
-	space.AddBody(player.body)
-	space.AddShape(player.shape)
-
-	player.prepareLivesIndicator()
-	drawOptionsLives.GeoM.Reset()
-	drawOptionsLives.GeoM.Scale(2.5, 2.5)
-
-	return player
+	return sprite
 }
 
-func (p *player) update(inp *input, rayHitInfo *cp.SegmentQueryInfo) {
-	// Update position
-	p.pos = p.body.Position()
-	// if p.numLives <= 0 {
-	// 	p.body.SetMoment(100)
-	// }
-
-	// Update gun position and angle
-	gunPosLeft := p.pos.Add(cp.Vector{X: -posGunRelative.X, Y: posGunRelative.Y})
-	gunPosRight := p.pos.Add(posGunRelative)
-
-	distX := cursorX - gunPosLeft.X
-	distY := cursorY - gunPosLeft.Y
-	angleGunLeft := math.Atan2(distY, distX)
+func (s *playerSprite) update() {
+	p := s.player
 
-	distX = cursorX - gunPosRight.X
-	distY = cursorY - gunPosRight.Y
-	angleGunRight := math.Atan2(distY, distX)
-
-	leftSaysTurnLeft := (angleGunLeft < -halfPi) || (angleGunLeft > halfPi)
-	rightSaysTurnLeft := (angleGunRight < -halfPi) || (angleGunRight > halfPi)
-
-	if leftSaysTurnLeft && rightSaysTurnLeft {
-		p.posGun = gunPosLeft
-		p.angleGun = angleGunLeft
-		p.turnedLeft = true
-	} else if !leftSaysTurnLeft && !rightSaysTurnLeft {
-		p.posGun = gunPosRight
-		p.angleGun = angleGunRight
-		p.turnedLeft = false
-	} else if p.turnedLeft {
-		p.posGun = gunPosLeft
-		p.angleGun = angleGunLeft
-	} else {
-		p.posGun = gunPosRight
-		p.angleGun = angleGunRight
-	}
-
-	p.checkOnGround()
-
-	// Raycast
-	const rayLength = gunRange
-	p.gunRay[0] = p.posGun
-	p.gunRay[1] = p.gunRay[0].Add(cp.Vector{
-		X: rayLength * math.Cos(p.angleGun), Y: rayLength * math.Sin(p.angleGun),
-	})
-
-	var inputt *input
-	if gameOver {
-		inputt = &input{}
-	} else {
-		inputt = inp
-	}
-	p.handleInputs(inputt, rayHitInfo)
-
-	switch p.state {
-	case stateWalking:
-		p.curAnim = animPlayerWalk
+	switch p.State {
+	case world.StateWalking:
+		s.curAnim = animPlayerWalk
 	default:
-		p.curAnim = animPlayerIdle
+		s.curAnim = animPlayerIdle
 	}
-
-	// v := p.body.Velocity()
-	// fmt.Printf("Friction: %.2f\tVel X: %.2f\tVel Y: %.2f\n", p.shape.Friction(), v.X, v.Y)
-	if p.numLives > 0 {
-		p.curAnim.Update(animDeltaTime)
+	if p.NumLives > 0 {
+		s.curAnim.Update(animDeltaTime)
 	}
-	p.updateDrawOptions()
-}
-
-func (p *player) checkOnGround() {
-	const groundNormalYThreshold = 0.8
-	// Grab the grounding normal from last frame - Taken from cp-examples/player and modified
-	groundNormal := cp.Vector{}
-	p.body.EachArbiter(func(arb *cp.Arbiter) {
-		n := arb.Normal() //.Neg()
-
-		if n.Y > groundNormal.Y {
-			groundNormal = n
-		}
-	})
-	p.onGround = groundNormal.Y > groundNormalYThreshold
-}
 
-func (p *player) handleInputs(input *input, rayHitInfo *cp.SegmentQueryInfo) {
-	p.state = stateIdle
-
-	// Handle inputs
-	var surfaceV cp.Vector
-	if input.right {
-		surfaceV.X = -playerVelocity
-		p.state = stateWalking
-	} else if input.left {
-		surfaceV.X = playerVelocity
-		p.state = stateWalking
-	}
-	p.shape.SetSurfaceV(surfaceV)
-	if p.onGround {
-		p.shape.SetFriction(playerFriction)
-	} else {
-		p.shape.SetFriction(0)
+	if p.NumLives != s.numLivesShown {
+		s.prepareLivesIndicator()
 	}
 
-	if input.up && p.onGround {
-		jumpV := math.Sqrt(2.0 * jumpHeightTile * tileLength * gravity) // Taken from cp-examples/player
-		p.body.SetVelocityVector(p.body.Velocity().Add(cp.Vector{X: 0, Y: -jumpV}))
-	}
-	// Apply air control if not on ground
-	if !p.onGround {
-		v := p.body.Velocity()
-		newVelX := cp.Clamp(v.X-surfaceV.X*deltaTimeSec, -playerVelocity, playerVelocity)
-		p.body.SetVelocity(newVelX, v.Y)
-		p.state = stateJumping
-	}
-
-	// Apply magnetic force if fire is pressed
-	p.gunForce = cp.Vector{}
-	if (input.gun != gunInputNone) && rayHitInfo.Alpha >= gunMinAlpha {
-		forceDirection := rayHitInfo.Point.Sub(p.pos).Normalize()
-		p.gunForce = forceDirection.Mult(gunForceMult).Mult(1 / (rayHitInfo.Alpha * rayHitInfo.Alpha))
-		p.gunForce = p.gunForce.Clamp(gunForceMax)
-
-		p.stateGun = gunStateAttract
-		if input.gun == gunInputRepel {
-			p.gunForce = p.gunForce.Neg()
-			p.stateGun = gunStateRepel
-		}
-		p.body.SetForce(p.gunForce)
-		p.state = stateFiring
-	} else {
-		p.stateGun = gunStateIdle
-	}
-	// v := p.body.Velocity()
-	// fmt.Printf("Velocity X: %.2f\tY: %.2f\t\tForce X: %.2f\tY:%.2f\n", v.X, v.Y, p.gunForce.X, p.gunForce.Y)
-	// fmt.Printf("Force X:%.2f\tY:%.2f\n", p.gunForce.X, p.gunForce.Y)
+	s.updateDrawOptions()
 }
 
-func (p *player) updateDrawOptions() {
-	// p.drawOptions.GeoM.Reset()
-	// p.drawOptions.GeoM.Rotate(p.body.Angle())
-	// p.drawOptions.GeoM.Concat(cam.GetTranslation(p.pos.X-tileLength/2.0, p.pos.Y-tileLength).GeoM)
-	p.drawOptions.GeoM.Reset()
-	cam.GetTranslation(&p.drawOptions, p.pos.X-tileLength/2.0, p.pos.Y-tileLength)
+func (s *playerSprite) updateDrawOptions() {
+	p := s.player
+
+	// s.drawOptions.GeoM.Reset()
+	// s.drawOptions.GeoM.Rotate(p.Body.Angle())
+	// s.drawOptions.GeoM.Concat(cam.GetTranslation(p.Pos.X-tileLength/2.0, p.Pos.Y-tileLength).GeoM)
+	s.drawOptions.GeoM.Reset()
+	cam.GetTranslation(&s.drawOptions, p.Pos.X-tileLength/2.0, p.Pos.Y-tileLength)
 
 	// Player
-	if p.angleGun < -halfPi || p.angleGun > halfPi {
-		p.drawOptionsAnim.ScaleX = -1.0
-		p.drawOptionsAnim.OriginX = 1.0
+	if p.AngleGun < -halfPi || p.AngleGun > halfPi {
+		s.drawOptionsAnim.ScaleX = -1.0
+		s.drawOptionsAnim.OriginX = 1.0
 	} else {
-		p.drawOptionsAnim.ScaleX = 1.0
-		p.drawOptionsAnim.OriginX = 0.0
+		s.drawOptionsAnim.ScaleX = 1.0
+		s.drawOptionsAnim.OriginX = 0.0
 	}
 
 	// Gun
-	p.drawOptionsGun.GeoM.Reset()
-	p.drawOptionsGun.GeoM.Translate(0, -p.sizeGun.Y/2.0)
-	p.drawOptionsGun.GeoM.Rotate(p.angleGun)
-	p.drawOptionsGun.GeoM.Concat(cam.GetTranslation(&ebiten.DrawImageOptions{}, p.posGun.X, p.posGun.Y).GeoM)
+	s.drawOptionsGun.GeoM.Reset()
+	s.drawOptionsGun.GeoM.Translate(0, -s.sizeGun/2.0)
+	s.drawOptionsGun.GeoM.Rotate(p.AngleGun)
+	s.drawOptionsGun.GeoM.Concat(cam.GetTranslation(&ebiten.DrawImageOptions{}, p.PosGun.X, p.PosGun.Y).GeoM)
 }
 
-func (p *player) hit() {
-	p.numLives--
-	p.prepareLivesIndicator()
-}
-
-func (p *player) prepareLivesIndicator() {
+func (s *playerSprite) prepareLivesIndicator() {
 	imageLives.Clear()
 	var drawOpt ebiten.DrawImageOptions
-	for iLife := 0; iLife < p.numLives; iLife++ {
+	for iLife := 0; iLife < s.player.NumLives; iLife++ {
 		drawOpt.GeoM.Reset()
 		drawOpt.GeoM.Translate(float64(iLife)*tileLength, 0)
 		imageLives.DrawImage(imageHeart, &drawOpt)
 	}
+	s.numLivesShown = s.player.NumLives
 }
 
-func (p *player) draw() {
+// drawHealth draws the health left of the player's current life.
+func (s *playerSprite) drawHealth(screen *ebiten.Image) {
+	if s.player.NumLives <= 0 {
+		return
+	}
+	ebitenutil.DrawRect(screen, healthBarX, healthBarY, healthBarWidth, healthBarHeight, colorBackground)
+	ebitenutil.DrawRect(screen, healthBarX, healthBarY, healthBarWidth*s.player.Health.Ratio(), healthBarHeight, colorPlayer)
+}
+
+func (s *playerSprite) draw() {
 	// Draw player
 	imagePlayer.Clear()
-	p.curAnim.Draw(imagePlayer, &p.drawOptionsAnim)
-	cam.Surface.DrawImage(imagePlayer, &p.drawOptions)
+	s.curAnim.Draw(imagePlayer, &s.drawOptionsAnim)
+	cam.Surface.DrawImage(imagePlayer, &s.drawOptions)
 
 	// Draw gun
-	if p.stateGun == gunStateAttract {
-		cam.Surface.DrawImage(imageGunAttract, &p.drawOptionsGun)
-	} else if p.stateGun == gunStateRepel {
-		cam.Surface.DrawImage(imageGunRepel, &p.drawOptionsGun)
+	if s.player.StateGun == world.GunStateAttract {
+		cam.Surface.DrawImage(imageGunAttract, &s.drawOptionsGun)
+	} else if s.player.StateGun == world.GunStateRepel {
+		cam.Surface.DrawImage(imageGunRepel, &s.drawOptionsGun)
 	} else {
-		cam.Surface.DrawImage(imageGunIdle, &p.drawOptionsGun)
+		cam.Surface.DrawImage(imageGunIdle, &s.drawOptionsGun)
 	}
 
 	// ebitenutil.DrawLine(dst, p.gunRay[0].X, p.gunRay[0].Y, p.gunRay[1].X, p.gunRay[1].Y, colorCrosshair)
 }
-
-func playerUpdateVelocity(body *cp.Body, gravity cp.Vector, damping, dt float64) {
-	body.UpdateVelocity(gravity, damping, dt)
-}
@@ -0,0 +1,23 @@
+// Copyright 2022 Anıl Konaç
+
+package main
+
+import (
+	"github.com/anilkonac/magrix/world"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+func init() {
+	registerEntityDrawer(world.ObjectTypePressurePlate, func(e world.Entity) {
+		drawPressurePlate(e.(*world.PressurePlate))
+	})
+}
+
+func drawPressurePlate(p *world.PressurePlate) {
+	pos := p.Position()
+	clr := colorOrange
+	if p.Pressed {
+		clr = colorGreen
+	}
+	ebitenutil.DrawRect(imageObjects, pos.X-p.Width/2.0, pos.Y-p.Height/2.0, p.Width, p.Height, clr)
+}
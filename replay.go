@@ -0,0 +1,162 @@
+// Copyright 2022 Anıl Konaç
+
+package main
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/anilkonac/magrix/world"
+)
+
+const replayVersion = 1
+
+// replayHeader starts a replay file. A replay only reproduces a run on the levels it was recorded on.
+type replayHeader struct {
+	Version  int
+	Levels   []string
+	FromDisk bool
+}
+
+// replayTick is the input of one tick of the game.
+type replayTick struct {
+	Controls     world.Input
+	Escape       bool
+	PausePlay    bool
+	WheelDy      float64
+	MusicToggle  bool
+	Reload       bool
+	Confirm      bool
+	RestartLevel bool
+	Quit         bool
+	NewGame      bool
+}
+
+func newReplayHeader(camp *campaign) replayHeader {
+	return replayHeader{
+		Version:  replayVersion,
+		Levels:   camp.levels,
+		FromDisk: camp.fromDisk,
+	}
+}
+
+// replayRecorder writes the input of every tick to a replay file.
+type replayRecorder struct {
+	file    *os.File
+	encoder *gob.Encoder
+}
+
+func newReplayRecorder(path string, camp *campaign) (*replayRecorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &replayRecorder{
+		file:    file,
+		encoder: gob.NewEncoder(file),
+	}
+	if err := r.encoder.Encode(newReplayHeader(camp)); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *replayRecorder) record(inp *input) error {
+	return r.encoder.Encode(replayTick{
+		Controls:     inp.Input,
+		Escape:       inp.escape,
+		PausePlay:    inp.pausePlay,
+		WheelDy:      inp.wheelDy,
+		MusicToggle:  inp.musicToggle,
+		Reload:       inp.reload,
+		Confirm:      inp.confirm,
+		RestartLevel: inp.restartLevel,
+		Quit:         inp.quit,
+		NewGame:      inp.newGame,
+	})
+}
+
+func (r *replayRecorder) close() error {
+	return r.file.Close()
+}
+
+// replayPlayer reads the input of every tick back from a replay file.
+type replayPlayer struct {
+	file    *os.File
+	decoder *gob.Decoder
+}
+
+func openReplay(path string, camp *campaign) (*replayPlayer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &replayPlayer{
+		file:    file,
+		decoder: gob.NewDecoder(file),
+	}
+	var header replayHeader
+	if err := r.decoder.Decode(&header); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	if err := header.check(newReplayHeader(camp)); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// check reports whether a replay with the header can be played on the campaign with the other header.
+func (h replayHeader) check(other replayHeader) error {
+	if h.Version != other.Version {
+		return fmt.Errorf("replay: version %d is not supported", h.Version)
+	}
+	if h.FromDisk != other.FromDisk || len(h.Levels) != len(other.Levels) {
+		return fmt.Errorf("replay: recorded on levels %v", h.Levels)
+	}
+	for iLevel := range h.Levels {
+		if h.Levels[iLevel] != other.Levels[iLevel] {
+			return fmt.Errorf("replay: recorded on levels %v", h.Levels)
+		}
+	}
+	return nil
+}
+
+// next fills the input with the next tick of the replay. It returns false when the replay has ended.
+func (r *replayPlayer) next(inp *input) (bool, error) {
+	var tick replayTick
+	if err := r.decoder.Decode(&tick); err != nil {
+		// A recording cut short may end in the middle of a tick
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return false, nil
+		}
+		return false, fmt.Errorf("replay: %w", err)
+	}
+
+	*inp = input{
+		Input:        tick.Controls,
+		escape:       tick.Escape,
+		pausePlay:    tick.PausePlay,
+		wheelDy:      tick.WheelDy,
+		musicToggle:  tick.MusicToggle,
+		reload:       tick.Reload,
+		confirm:      tick.Confirm,
+		restartLevel: tick.RestartLevel,
+		quit:         tick.Quit,
+		newGame:      tick.NewGame,
+	}
+	return true, nil
+}
+
+func (r *replayPlayer) close() error {
+	return r.file.Close()
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 Anıl Konaç
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/anilkonac/magrix/world"
+	"github.com/jakecoffman/cp"
+)
+
+func TestReplayRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.replay")
+	camp := newCampaign(campaignLevels)
+
+	recorded := []input{
+		{Input: world.Input{Right: true, Aim: cp.Vector{X: 10, Y: 20}}},
+		{Input: world.Input{Up: true, Gun: world.GunInputAttract}, wheelDy: -1},
+		{Input: world.Input{Gun: world.GunInputRepel, Activate: true}, pausePlay: true},
+		{escape: true, musicToggle: true, reload: true},
+		{confirm: true, restartLevel: true, quit: true, newGame: true},
+	}
+	recorder, err := newReplayRecorder(path, camp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for iTick := range recorded {
+		if err := recorder.record(&recorded[iTick]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := recorder.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	player, err := openReplay(path, camp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer player.close()
+	for iTick, want := range recorded {
+		var got input
+		ok, err := player.next(&got)
+		if err != nil || !ok {
+			t.Fatalf("tick %d: ok %v, err %v", iTick, ok, err)
+		}
+		if got != want {
+			t.Errorf("tick %d: got %+v, want %+v", iTick, got, want)
+		}
+	}
+	var extra input
+	if ok, err := player.next(&extra); ok || err != nil {
+		t.Fatalf("replay goes on after the recorded ticks: ok %v, err %v", ok, err)
+	}
+}
+
+func TestReplayOfOtherLevels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.replay")
+	recorder, err := newReplayRecorder(path, newCampaign(campaignLevels))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.close()
+
+	if _, err := openReplay(path, newCampaignFromDisk("other.tmx")); err == nil {
+		t.Fatal("replay is opened on other levels")
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 Anıl Konaç
+
+package main
+
+import (
+	"image/color"
+
+	"github.com/anilkonac/magrix/world"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/jakecoffman/cp"
+	"github.com/yohamta/ganim8/v2"
+)
+
+const explosionTotalDurationMs = durationExplosionMs * 14
+
+var (
+	colorHoming  = color.RGBA{255, 120, 120, 255}
+	colorGrenade = color.RGBA{90, 110, 60, 255}
+	colorPlasma  = color.RGBA{120, 200, 255, 255}
+	colorLaser   = color.RGBA{255, 60, 60, 255}
+)
+
+type explosion struct {
+	drawOptions ganim8.DrawOptions
+	elapsedMs   int64
+	animation   ganim8.Animation
+}
+
+func newExplosion(pos cp.Vector) *explosion {
+	explo := &explosion{
+		drawOptions: ganim8.DrawOptions{
+			X:       pos.X,
+			Y:       pos.Y,
+			ScaleX:  1.0,
+			ScaleY:  1.0,
+			OriginX: 0.5,
+			OriginY: 0.5,
+		},
+		animation: *animExplosion,
+	}
+
+	return explo
+}
+
+// updateExplosions starts the explosions of the last step and plays the ongoing ones.
+func (g *game) updateExplosions() {
+	animRocket.Update(animDeltaTime)
+
+	for _, event := range g.world.Events {
+		switch event.Kind {
+		case world.EventRocketHit:
+			g.explosions = append(g.explosions, newExplosion(event.Pos))
+			playerExplosion.Rewind()
+			playerExplosion.Play()
+		case world.EventEnemyExploded:
+			g.explosions = append(g.explosions, newExplosion(event.Pos))
+		}
+	}
+
+	// Update explosion animations
+	explosionsToBeDeleted := make([]*explosion, 0, 8)
+	for _, explo := range g.explosions {
+		explo.animation.Update(animDeltaTime)
+		explo.elapsedMs += animDeltaTime.Milliseconds()
+		if explo.elapsedMs >= explosionTotalDurationMs {
+			explosionsToBeDeleted = append(explosionsToBeDeleted, explo)
+		}
+	}
+
+	// Delete ended explosion animation
+	for iExplo, explo := range g.explosions {
+		for _, exploTarget := range explosionsToBeDeleted {
+			if explo == exploTarget {
+				copy(g.explosions[iExplo:], g.explosions[iExplo+1:])
+				g.explosions[len(g.explosions)-1] = nil
+				g.explosions = g.explosions[:len(g.explosions)-1]
+
+				exploTarget = nil
+			}
+		}
+
+	}
+}
+
+func (g *game) drawRockets() {
+	// Draw rockets
+	drawOptions := ganim8.DrawOptions{
+		ScaleX:  1.0,
+		ScaleY:  1.0,
+		OriginX: 0.5,
+		OriginY: 0.5,
+	}
+	for _, rocket := range g.world.Rockets() {
+		pos := rocket.Body.Position()
+		switch rocket.Kind {
+		case world.RocketKindGrenade:
+			drawOrb(pos, rocket.Width(), colorGrenade)
+		case world.RocketKindPlasma:
+			drawOrb(pos, rocket.Width(), colorPlasma)
+		default:
+			drawOptions.X = pos.X
+			drawOptions.Y = pos.Y
+			drawOptions.Rotate = rocket.Body.Angle()
+			drawOptions.ColorM.Reset()
+			if rocket.Kind == world.RocketKindHoming {
+				drawOptions.ColorM.ScaleWithColor(colorHoming)
+			}
+			animRocket.Draw(imageObjects, &drawOptions)
+		}
+	}
+
+	// Draw lasers fading out
+	for _, laser := range g.world.Lasers() {
+		clr := colorLaser
+		clr.A = uint8(255 * laser.ShowSec / world.LaserShowSec)
+		ebitenutil.DrawLine(imageObjects, laser.Start.X, laser.Start.Y, laser.End.X, laser.End.Y, clr)
+	}
+
+	// Draw explosions
+	for _, explo := range g.explosions {
+		explo.animation.Draw(imageObjects, &explo.drawOptions)
+	}
+}
+
+// drawOrb draws a round rocket by scaling the ray hit circle.
+func drawOrb(pos cp.Vector, diameter float64, clr color.Color) {
+	var op ebiten.DrawImageOptions
+	scale := diameter / rayHitImageWidth
+	op.GeoM.Translate(-rayHitImageWidth/2.0, -rayHitImageWidth/2.0)
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(pos.X, pos.Y)
+	op.ColorM.ScaleWithColor(clr)
+	imageObjects.DrawImage(imageRayHit, &op)
+}
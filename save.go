@@ -0,0 +1,88 @@
+// Copyright 2022 Anıl Konaç
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/anilkonac/magrix/world"
+)
+
+// saveData is the progress of the campaign kept in the save file.
+type saveData struct {
+	Level         int
+	ElapsedSec    float64
+	EnemiesKilled int
+	Progress      *world.Progress `json:",omitempty"` // progress in the level, nil if no checkpoint is reached yet
+}
+
+// defaultSavePath returns the path of the save file in the user's config directory.
+func defaultSavePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "magrix", "save.json"), nil
+}
+
+// load restores the campaign from its save file if there is one.
+func (c *campaign) load() error {
+	bytes, err := os.ReadFile(c.savePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var data saveData
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return err
+	}
+	if data.Level < 0 || data.Level >= len(c.levels) {
+		return nil
+	}
+
+	c.iLevel = data.Level
+	c.elapsedSec = data.ElapsedSec
+	c.enemiesKilled = data.EnemiesKilled
+	c.progress = data.Progress
+	return nil
+}
+
+// save writes the campaign to its save file. Errors are only logged so that the game can go on.
+func (c *campaign) save() {
+	if c.savePath == "" {
+		return
+	}
+
+	bytes, err := json.MarshalIndent(saveData{
+		Level:         c.iLevel,
+		ElapsedSec:    c.elapsedSec,
+		EnemiesKilled: c.enemiesKilled,
+		Progress:      c.progress,
+	}, "", "\t")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(c.savePath), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(c.savePath, bytes, 0o644)
+	}
+	if err != nil {
+		log.Printf("Progress could not be saved: %v", err)
+	}
+}
+
+// deleteSave removes the save file of the campaign.
+func (c *campaign) deleteSave() {
+	if c.savePath == "" {
+		return
+	}
+	if err := os.Remove(c.savePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("Save file could not be removed: %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2022 Anıl Konaç
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var colorOverlay = color.RGBA{0, 0, 0, 160}
+
+// gameState is a scene of the game flow. The game keeps a stack of states:
+// only the top one is updated, and all of them are drawn from bottom to top,
+// so that a state like the pause menu can be drawn over the level.
+type gameState interface {
+	update(g *game)
+	draw(g *game, screen *ebiten.Image)
+}
+
+func (g *game) state() gameState {
+	return g.states[len(g.states)-1]
+}
+
+func (g *game) pushState(s gameState) {
+	g.states = append(g.states, s)
+}
+
+func (g *game) popState() {
+	g.states[len(g.states)-1] = nil
+	g.states = g.states[:len(g.states)-1]
+}
+
+// setState replaces the whole stack with the given state.
+func (g *game) setState(s gameState) {
+	g.states = []gameState{s}
+}
+
+func (g *game) isPaused() bool {
+	_, paused := g.state().(*pausedState)
+	return paused
+}
+
+// titleState is the screen shown when the game starts.
+type titleState struct{}
+
+func (s *titleState) update(g *game) {
+	switch {
+	case g.input.confirm:
+		g.setState(&playingState{})
+	case g.input.newGame && g.campaign.hasProgress():
+		g.campaign.reset()
+		if g.restart() == nil {
+			g.setState(&playingState{})
+		}
+	}
+}
+
+func (s *titleState) draw(g *game, screen *ebiten.Image) {
+	screen.Fill(colorBackground)
+	screen.DrawImage(imageTextTitle, &drawOptionsTextTitle)
+	if g.campaign.hasProgress() {
+		screen.DrawImage(imageTextTitleContinue, &drawOptionsTextTitleContinue)
+	} else {
+		screen.DrawImage(imageTextTitlePrompt, &drawOptionsTextTitlePrompt)
+	}
+}
+
+// playingState runs the level.
+type playingState struct{}
+
+func (s *playingState) update(g *game) {
+	if g.input.pausePlay {
+		g.pushState(newPausedState())
+		return
+	}
+
+	g.campaign.elapsedSec += deltaTimeSec
+	g.step()
+
+	if g.world.Player.NumLives <= 0 {
+		g.pushState(&gameOverState{})
+	} else if g.world.Completed {
+		g.pushState(&levelCompleteState{})
+	}
+}
+
+func (s *playingState) draw(g *game, screen *ebiten.Image) {
+	g.drawLevel(screen)
+}
+
+// pausedState is the pause menu drawn over the level.
+type pausedState struct{}
+
+func newPausedState() *pausedState {
+	if musicState == musicOn {
+		playerMusic.Pause()
+		musicState = musicPaused
+	}
+	return &pausedState{}
+}
+
+func (s *pausedState) update(g *game) {
+	switch {
+	case g.input.pausePlay:
+		s.resumeMusic()
+		g.popState()
+	case g.input.restartLevel:
+		s.resumeMusic()
+		g.campaign.progress = nil
+		g.campaign.save()
+		if g.restart() == nil {
+			g.setState(&playingState{})
+		}
+	case g.input.quit:
+		s.resumeMusic()
+		g.quitToTitle()
+	}
+}
+
+func (s *pausedState) resumeMusic() {
+	if musicState == musicPaused {
+		playerMusic.Play()
+		musicState = musicOn
+	}
+}
+
+func (s *pausedState) draw(g *game, screen *ebiten.Image) {
+	screen.Fill(colorOverlay)
+	screen.DrawImage(imageTextPaused, &drawOptionsTextPaused)
+	screen.DrawImage(imageTextPausedMenu, &drawOptionsTextPausedMenu)
+}
+
+// gameOverState lets the level go on without player control for a while, then restarts it.
+type gameOverState struct {
+	elapsedSec float64
+}
+
+func (s *gameOverState) update(g *game) {
+	g.input.clearControls()
+	g.step()
+
+	s.elapsedSec += deltaTimeSec
+	if s.elapsedSec < restartTimeSec {
+		return
+	}
+	if g.restart() != nil {
+		s.elapsedSec = 0 // try again after a while, the map may be fixed by then
+		return
+	}
+	g.setState(&playingState{})
+}
+
+func (s *gameOverState) draw(g *game, screen *ebiten.Image) {
+	screen.DrawImage(imageTextFail, &drawOptionsTextFail)
+}
+
+// levelCompleteState lets the level go on without player control for a while, then moves on to the next level.
+// After the last level the results are shown.
+type levelCompleteState struct {
+	elapsedSec float64
+}
+
+func (s *levelCompleteState) update(g *game) {
+	g.input.clearControls()
+	g.step()
+
+	s.elapsedSec += deltaTimeSec
+	if s.elapsedSec < levelCompleteSec {
+		return
+	}
+
+	if g.campaign.isLastLevel() {
+		g.setState(&resultsState{
+			livesLeft: g.world.Player.NumLives,
+		})
+		return
+	}
+	if g.campaign.advance(g.restart) != nil {
+		s.elapsedSec = 0 // try again after a while, the map may be fixed by then
+		return
+	}
+	g.setState(&playingState{})
+}
+
+func (s *levelCompleteState) draw(g *game, screen *ebiten.Image) {
+	if g.campaign.isLastLevel() {
+		screen.DrawImage(imageTextButton, &drawOptionsTextButton)
+	} else {
+		screen.DrawImage(imageTextLevelComplete, &drawOptionsTextLevelComplete)
+	}
+}
+
+// resultsState is the screen shown after the campaign is completed.
+type resultsState struct {
+	livesLeft int
+}
+
+func (s *resultsState) update(g *game) {
+	if g.input.confirm {
+		g.campaign.reset()
+		g.quitToTitle()
+	}
+}
+
+func (s *resultsState) draw(g *game, screen *ebiten.Image) {
+	const lineHeight = fontSizeIntro * 1.5
+
+	screen.Fill(colorBackground)
+	drawTextCentered(screen, textButton, fontFaceButton, colorGreen, -2*menuTextShiftY)
+
+	elapsedSec := int(g.campaign.elapsedSec)
+	lines := [...]string{
+		fmt.Sprintf("Time: %02d:%02d", elapsedSec/60, elapsedSec%60),
+		fmt.Sprintf("Enemies destroyed: %d", g.campaign.enemiesKilled),
+		fmt.Sprintf("Lives left: %d", s.livesLeft),
+	}
+	for iLine, line := range lines {
+		drawTextCentered(screen, line, fontFaceIntro, colorCrosshair, -menuTextShiftY+float64(iLine)*lineHeight)
+	}
+	drawTextCentered(screen, textResultsPrompt, fontFaceIntro, colorGreen, introTextShiftY)
+}
+
+// quitToTitle saves the campaign and goes back to the title screen, from where it can be continued.
+func (g *game) quitToTitle() {
+	g.campaign.save()
+	if g.restart() == nil {
+		g.setState(&titleState{})
+	}
+}
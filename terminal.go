@@ -1,64 +1,59 @@
 package main
 
 import (
-	"math"
-
+	"github.com/anilkonac/magrix/world"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/jakecoffman/cp"
-	"github.com/lafriks/go-tiled"
 	"github.com/yohamta/ganim8/v2"
 )
 
-type terminal struct {
-	pos         cp.Vector
-	shape       *cp.Shape
-	drawOptions ganim8.DrawOptions
-	spr         *ganim8.Sprite
-	triggered   bool
+func init() {
+	registerEntityDrawer(world.ObjectTypeTerminal, func(e world.Entity) {
+		drawTerminal(e.(*world.Terminal))
+	})
 }
 
-func newTerminal(obj *tiled.Object, space *cp.Space) *terminal {
-	var shape *cp.Shape
-	if obj.Properties.GetBool("blocking") {
-		radius := math.Min(obj.Width, obj.Height) / 2.0
-		x2 := obj.X + obj.Width - radius
-		y2 := obj.Y + obj.Height - radius
-		shape = space.AddShape(cp.NewSegment(space.StaticBody, cp.Vector{X: obj.X + radius, Y: obj.Y + radius}, cp.Vector{X: x2, Y: y2}, radius))
-		// shape.SetElasticity(wallElasticity)
-		// shape.SetFriction(wallFriction)
+// findTerminal returns the first terminal of the color, or nil if there is none.
+func findTerminal(entities []world.Entity, color string) *world.Terminal {
+	for _, entity := range entities {
+		if terminal, ok := entity.(*world.Terminal); ok && terminal.Color == color {
+			return terminal
+		}
 	}
+	return nil
+}
 
-	var drawOpts ebiten.DrawImageOptions
-	drawOpts.GeoM.Reset()
-	drawOpts.GeoM.Translate(obj.X+obj.Width/2.0, obj.Y+obj.Height/2.0)
-
+func drawTerminal(t *world.Terminal) {
+	pos := t.Position()
 	spr := spriteTerminalBlue
-	if obj.Properties.GetBool("isOrange") {
+	switch t.Color {
+	case world.ColorNameOrange:
 		spr = spriteTerminalOrange
+	case world.ColorNameGreen:
+		spr = spriteTerminalGreen
 	}
-	return &terminal{
-		shape: shape,
-		drawOptions: ganim8.DrawOptions{
-			X:       obj.X + obj.Width/2.0,
-			Y:       obj.Y + obj.Height/2.0,
-			OriginX: 0.5,
-			OriginY: 0.5,
-			ScaleX:  1.0,
-			ScaleY:  1.0,
-		},
-		spr: spr,
-		pos: cp.Vector{X: obj.X + obj.Width/2.0, Y: obj.Y + obj.Height/2.0},
-	}
-}
 
-func (t *terminal) trigger() {
-	t.triggered = true
-}
-
-func (t *terminal) draw() {
 	var index int
-	if t.triggered {
+	if t.Triggered {
 		index = 1
 	}
-	t.spr.Draw(imageObjects, index, &t.drawOptions)
+	spr.Draw(imageObjects, index, &ganim8.DrawOptions{
+		X:       pos.X,
+		Y:       pos.Y,
+		OriginX: 0.5,
+		OriginY: 0.5,
+		ScaleX:  1.0,
+		ScaleY:  1.0,
+	})
+}
+
+// drawTerminalText draws the text telling what the terminal does.
+func drawTerminalText(screen *ebiten.Image, t *world.Terminal) {
+	switch t.Color {
+	case world.ColorNameGreen:
+		screen.DrawImage(imageTextIntro, &drawOptionsTextIntro)
+	case world.ColorNameBlue:
+		screen.DrawImage(imageTextTerminalBlue, &drawOptionsTextTerminalBlue)
+	case world.ColorNameOrange:
+		screen.DrawImage(imageTextTerminalOrange, &drawOptionsTextTerminalOrange)
+	}
 }
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"image/color"
+
 	"github.com/anilkonac/magrix/asset"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text"
@@ -9,36 +11,52 @@ import (
 )
 
 const (
-	dpi                   = 72
-	fontSizeIntro         = 24
-	fontSizeButton        = 48
-	textIntro             = "Locating terminals controlling plasma walls..."
-	textTerminalBlue      = "Disabling the blue plasma wall..."
-	textTerminalOrange    = "Disabling the orange plasma wall..."
-	textButton            = "Mission Accomplished!"
-	textFail              = "Mission Failed!"
-	durationTextIntroSec  = 2.5
-	durationTextTerminals = 2.0
-	introTextShiftY       = screenHeight / 4.0
+	dpi                = 72
+	fontSizeIntro      = 24
+	fontSizeButton     = 48
+	fontSizeTitle      = 96
+	textIntro          = "Locating terminals controlling plasma walls..."
+	textTerminalBlue   = "Disabling the blue plasma wall..."
+	textTerminalOrange = "Disabling the orange plasma wall..."
+	textButton         = "Mission Accomplished!"
+	textLevelComplete  = "Level Complete!"
+	textFail           = "Mission Failed!"
+	textTitle          = "MAGRIX"
+	textTitlePrompt    = "Press Enter to start"
+	textTitleContinue  = "Enter: Continue    N: New Game"
+	textPaused         = "Paused"
+	textPausedMenu     = "P: Resume    R: Restart Level    Q: Quit to Title"
+	textResultsPrompt  = "Press Enter to return to the title screen"
+	introTextShiftY    = screenHeight / 4.0
+	menuTextShiftY     = screenHeight / 8.0
 )
 
 var (
 	fontFaceIntro                 font.Face
 	fontFaceButton                font.Face
-	showTextIntro                 bool
-	showTextTerminalBlue          bool
-	showTextTerminalOrange        bool
-	showTextButton                bool
+	fontFaceTitle                 font.Face
 	imageTextIntro                *ebiten.Image
 	imageTextTerminalBlue         *ebiten.Image
 	imageTextTerminalOrange       *ebiten.Image
 	imageTextButton               *ebiten.Image
+	imageTextLevelComplete        *ebiten.Image
 	imageTextFail                 *ebiten.Image
+	imageTextTitle                *ebiten.Image
+	imageTextTitlePrompt          *ebiten.Image
+	imageTextTitleContinue        *ebiten.Image
+	imageTextPaused               *ebiten.Image
+	imageTextPausedMenu           *ebiten.Image
 	drawOptionsTextIntro          ebiten.DrawImageOptions
 	drawOptionsTextTerminalBlue   ebiten.DrawImageOptions
 	drawOptionsTextTerminalOrange ebiten.DrawImageOptions
 	drawOptionsTextButton         ebiten.DrawImageOptions
+	drawOptionsTextLevelComplete  ebiten.DrawImageOptions
 	drawOptionsTextFail           ebiten.DrawImageOptions
+	drawOptionsTextTitle          ebiten.DrawImageOptions
+	drawOptionsTextTitlePrompt    ebiten.DrawImageOptions
+	drawOptionsTextTitleContinue  ebiten.DrawImageOptions
+	drawOptionsTextPaused         ebiten.DrawImageOptions
+	drawOptionsTextPausedMenu     ebiten.DrawImageOptions
 )
 
 func init() {
@@ -59,53 +77,62 @@ func init() {
 	})
 	panicErr(err)
 
+	fontFaceTitle, err = opentype.NewFace(tt, &opentype.FaceOptions{
+		Size:    fontSizeTitle,
+		DPI:     dpi,
+		Hinting: font.HintingFull,
+	})
+	panicErr(err)
+
 	// Prepare intro text
-	boundText := text.BoundString(fontFaceIntro, textIntro)
-	boundTextSize := boundText.Size()
-	imageTextIntro = ebiten.NewImage(boundTextSize.X, boundTextSize.Y)
-	text.Draw(imageTextIntro, textIntro, fontFaceIntro, -boundText.Min.X, -boundText.Min.Y, colorGreen)
-	drawOptionsTextIntro.GeoM.Reset()
-	drawOptionsTextIntro.GeoM.Translate(
-		float64((screenWidth-boundTextSize.X)/2.0-boundText.Min.X),
-		float64((screenHeight-boundTextSize.Y)/2.0-boundText.Min.Y)+introTextShiftY)
+	imageTextIntro = prepareText(textIntro, fontFaceIntro, colorGreen, introTextShiftY, &drawOptionsTextIntro)
 
 	// Prepare blue terminal text
-	boundText = text.BoundString(fontFaceIntro, textTerminalBlue)
-	boundTextSize = boundText.Size()
-	imageTextTerminalBlue = ebiten.NewImage(boundTextSize.X, boundTextSize.Y)
-	text.Draw(imageTextTerminalBlue, textTerminalBlue, fontFaceIntro, -boundText.Min.X, -boundText.Min.Y, colorBlue)
-	drawOptionsTextTerminalBlue.GeoM.Reset()
-	drawOptionsTextTerminalBlue.GeoM.Translate(
-		float64((screenWidth-boundTextSize.X)/2.0-boundText.Min.X),
-		float64((screenHeight-boundTextSize.Y)/2.0-boundText.Min.Y)+introTextShiftY)
+	imageTextTerminalBlue = prepareText(textTerminalBlue, fontFaceIntro, colorBlue, introTextShiftY, &drawOptionsTextTerminalBlue)
 
 	// Prepare orange terminal text
-	boundText = text.BoundString(fontFaceIntro, textTerminalOrange)
-	boundTextSize = boundText.Size()
-	imageTextTerminalOrange = ebiten.NewImage(boundTextSize.X, boundTextSize.Y)
-	text.Draw(imageTextTerminalOrange, textTerminalOrange, fontFaceIntro, -boundText.Min.X, -boundText.Min.Y, colorOrange)
-	drawOptionsTextTerminalOrange.GeoM.Reset()
-	drawOptionsTextTerminalOrange.GeoM.Translate(
-		float64((screenWidth-boundTextSize.X)/2.0-boundText.Min.X),
-		float64((screenHeight-boundTextSize.Y)/2.0-boundText.Min.Y)+introTextShiftY)
+	imageTextTerminalOrange = prepareText(textTerminalOrange, fontFaceIntro, colorOrange, introTextShiftY, &drawOptionsTextTerminalOrange)
 
 	// Prepare final(button) text
-	boundText = text.BoundString(fontFaceButton, textButton)
-	boundTextSize = boundText.Size()
-	imageTextButton = ebiten.NewImage(boundTextSize.X, boundTextSize.Y)
-	text.Draw(imageTextButton, textButton, fontFaceButton, -boundText.Min.X, -boundText.Min.Y, colorGreen)
-	drawOptionsTextButton.GeoM.Reset()
-	drawOptionsTextButton.GeoM.Translate(
-		float64((screenWidth-boundTextSize.X)/2.0-boundText.Min.X),
-		float64((screenHeight-boundTextSize.Y)/2.0-boundText.Min.Y)+introTextShiftY)
+	imageTextButton = prepareText(textButton, fontFaceButton, colorGreen, introTextShiftY, &drawOptionsTextButton)
+
+	// Prepare level complete text
+	imageTextLevelComplete = prepareText(textLevelComplete, fontFaceButton, colorGreen, introTextShiftY, &drawOptionsTextLevelComplete)
 
 	// Prepare Fail text
-	boundText = text.BoundString(fontFaceButton, textFail)
-	boundTextSize = boundText.Size()
-	imageTextFail = ebiten.NewImage(boundTextSize.X, boundTextSize.Y)
-	text.Draw(imageTextFail, textFail, fontFaceButton, -boundText.Min.X, -boundText.Min.Y, colorGunAttract)
-	drawOptionsTextFail.GeoM.Reset()
-	drawOptionsTextFail.GeoM.Translate(
+	imageTextFail = prepareText(textFail, fontFaceButton, colorGunAttract, introTextShiftY, &drawOptionsTextFail)
+
+	// Prepare title screen texts
+	imageTextTitle = prepareText(textTitle, fontFaceTitle, colorPlayer, -menuTextShiftY, &drawOptionsTextTitle)
+	imageTextTitlePrompt = prepareText(textTitlePrompt, fontFaceIntro, colorCrosshair, menuTextShiftY, &drawOptionsTextTitlePrompt)
+	imageTextTitleContinue = prepareText(textTitleContinue, fontFaceIntro, colorCrosshair, menuTextShiftY, &drawOptionsTextTitleContinue)
+
+	// Prepare pause menu texts
+	imageTextPaused = prepareText(textPaused, fontFaceButton, colorCrosshair, -menuTextShiftY, &drawOptionsTextPaused)
+	imageTextPausedMenu = prepareText(textPausedMenu, fontFaceIntro, colorCrosshair, menuTextShiftY, &drawOptionsTextPausedMenu)
+}
+
+// prepareText renders the string to a new image and prepares draw options
+// that place it at the horizontal center of the screen, shiftY below the vertical center.
+func prepareText(str string, face font.Face, clr color.Color, shiftY float64, drawOptions *ebiten.DrawImageOptions) *ebiten.Image {
+	boundText := text.BoundString(face, str)
+	boundTextSize := boundText.Size()
+	image := ebiten.NewImage(boundTextSize.X, boundTextSize.Y)
+	text.Draw(image, str, face, -boundText.Min.X, -boundText.Min.Y, clr)
+	drawOptions.GeoM.Reset()
+	drawOptions.GeoM.Translate(
 		float64((screenWidth-boundTextSize.X)/2.0-boundText.Min.X),
-		float64((screenHeight-boundTextSize.Y)/2.0-boundText.Min.Y)+introTextShiftY)
+		float64((screenHeight-boundTextSize.Y)/2.0-boundText.Min.Y)+shiftY)
+
+	return image
+}
+
+// drawTextCentered draws the string at the horizontal center of the screen, shiftY below the vertical center.
+// It is meant for texts that change, others are prepared once with prepareText.
+func drawTextCentered(screen *ebiten.Image, str string, face font.Face, clr color.Color, shiftY float64) {
+	boundText := text.BoundString(face, str)
+	boundTextSize := boundText.Size()
+	text.Draw(screen, str, face,
+		(screenWidth-boundTextSize.X)/2.0-boundText.Min.X,
+		(screenHeight-boundTextSize.Y)/2.0-boundText.Min.Y+int(shiftY), clr)
 }
@@ -0,0 +1,229 @@
+// Copyright 2022 Anıl Konaç
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/anilkonac/magrix/world"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/lafriks/go-tiled"
+)
+
+// Name of the tile layer that is drawn in front of the objects.
+// All other tile layers are drawn behind them.
+const layerNamePlatforms = world.LayerNamePlatforms
+
+// imageLoader loads the image at the given path, which is relative to the map's source.
+type imageLoader func(path string) (*ebiten.Image, error)
+
+// mapLayer returns the tile layer with the given name.
+func mapLayer(gameMap *tiled.Map, name string) (*tiled.Layer, error) {
+	for _, layer := range gameMap.Layers {
+		if layer.Name == name {
+			return layer, nil
+		}
+	}
+	return nil, fmt.Errorf("map: tile layer %q not found", name)
+}
+
+// tileAnimation is the frame state of an animated tileset tile.
+// All cells showing the same tile share it, so they stay in sync like they do in Tiled.
+type tileAnimation struct {
+	tileset   *tiled.Tileset
+	frames    []*tiled.AnimationFrame
+	iFrame    int
+	elapsedMs int64
+	changed   bool
+}
+
+func (a *tileAnimation) update() {
+	a.changed = false
+	a.elapsedMs += animDeltaTime.Milliseconds()
+	for a.elapsedMs >= int64(a.frames[a.iFrame].Duration) {
+		if a.frames[a.iFrame].Duration == 0 {
+			a.elapsedMs = 0
+			break
+		}
+		a.elapsedMs -= int64(a.frames[a.iFrame].Duration)
+		a.iFrame = (a.iFrame + 1) % len(a.frames)
+		a.changed = true
+	}
+}
+
+// cellTile is one of the tiles stacked in a cell of a tile layer image.
+type cellTile struct {
+	tileset     *tiled.Tileset
+	image       *ebiten.Image
+	tileID      uint32
+	anim        *tileAnimation
+	drawOptions ebiten.DrawImageOptions
+}
+
+func (t *cellTile) draw(dst *ebiten.Image) {
+	tileID := t.tileID
+	if t.anim != nil {
+		tileID = t.anim.frames[t.anim.iFrame].TileID
+	}
+	dst.DrawImage(t.image.SubImage(t.tileset.GetTileRect(tileID)).(*ebiten.Image), &t.drawOptions)
+}
+
+// tileCell is a cell of a tile layer image with all the tiles drawn to it.
+type tileCell struct {
+	area     image.Rectangle
+	tiles    []*cellTile
+	animated bool
+}
+
+// tileLayer is a set of tile layers rendered to an image.
+// Cells containing animated tiles are redrawn when their animation frame changes.
+type tileLayer struct {
+	image      *ebiten.Image
+	animations []*tileAnimation
+	cells      []*tileCell
+}
+
+// backgroundLayers returns all tile layers except the platforms layer, in the order they are drawn in Tiled.
+func backgroundLayers(gameMap *tiled.Map) []*tiled.Layer {
+	var layers []*tiled.Layer
+	for _, layer := range gameMap.Layers {
+		if layer.Name != layerNamePlatforms {
+			layers = append(layers, layer)
+		}
+	}
+	return layers
+}
+
+// newTileLayer renders the given tile layers on top of each other to an image of the map's size.
+func newTileLayer(gameMap *tiled.Map, loadImage imageLoader, layers ...*tiled.Layer) (*tileLayer, error) {
+	l := &tileLayer{
+		image: ebiten.NewImage(gameMap.Width*gameMap.TileWidth, gameMap.Height*gameMap.TileHeight),
+	}
+
+	cells := make([]*tileCell, gameMap.Width*gameMap.Height)
+	animations := make(map[*tiled.TilesetTile]*tileAnimation)
+	for _, layer := range layers {
+		if !layer.Visible || layer.IsEmpty() {
+			continue
+		}
+
+		for iTile, tile := range layer.Tiles {
+			if tile.IsNil() {
+				continue
+			}
+			x, y := layer.GetTilePosition(iTile)
+
+			tilesetImage, err := loadTilesetImage(tile.Tileset, loadImage)
+			if err != nil {
+				return nil, err
+			}
+			cTile := &cellTile{
+				tileset: tile.Tileset,
+				image:   tilesetImage,
+				tileID:  tile.ID,
+			}
+			tileGeoM(&cTile.drawOptions.GeoM, tile, gameMap.TileHeight, x, y)
+			cTile.drawOptions.ColorM.Scale(1, 1, 1, float64(layer.Opacity))
+
+			if tilesetTile, err := tile.Tileset.GetTilesetTile(tile.ID); err == nil && len(tilesetTile.Animation) > 0 {
+				anim, ok := animations[tilesetTile]
+				if !ok {
+					anim = &tileAnimation{
+						tileset: tile.Tileset,
+						frames:  tilesetTile.Animation,
+					}
+					animations[tilesetTile] = anim
+					l.animations = append(l.animations, anim)
+				}
+				cTile.anim = anim
+			}
+
+			cell := cells[iTile]
+			if cell == nil {
+				cell = &tileCell{
+					area: image.Rect(x, y, x+gameMap.TileWidth, y+gameMap.TileHeight),
+				}
+				cells[iTile] = cell
+			}
+			cell.tiles = append(cell.tiles, cTile)
+			cell.animated = cell.animated || (cTile.anim != nil)
+
+			cTile.draw(l.image)
+		}
+	}
+
+	// Keep only the cells that need to be redrawn
+	for _, cell := range cells {
+		if cell != nil && cell.animated {
+			l.cells = append(l.cells, cell)
+		}
+	}
+
+	return l, nil
+}
+
+// update advances tile animations and redraws the cells whose frame has changed.
+func (l *tileLayer) update() {
+	for _, anim := range l.animations {
+		anim.update()
+	}
+
+	for _, cell := range l.cells {
+		changed := false
+		for _, tile := range cell.tiles {
+			if tile.anim != nil && tile.anim.changed {
+				changed = true
+				break
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		l.image.SubImage(cell.area).(*ebiten.Image).Clear()
+		for _, tile := range cell.tiles {
+			tile.draw(l.image)
+		}
+	}
+}
+
+// tileGeoM prepares the transformation that places the tile at (x, y) of the layer.
+// Flips are applied in the order Tiled applies them: diagonal first, then horizontal and vertical.
+func tileGeoM(geoM *ebiten.GeoM, tile *tiled.LayerTile, mapTileHeight, x, y int) {
+	tileset := tile.Tileset
+	halfW := float64(tileset.TileWidth) / 2.0
+	halfH := float64(tileset.TileHeight) / 2.0
+
+	geoM.Reset()
+	geoM.Translate(-halfW, -halfH)
+	if tile.DiagonalFlip {
+		geoM.Rotate(math.Pi / 2.0)
+		geoM.Scale(-1, 1)
+	}
+	if tile.HorizontalFlip {
+		geoM.Scale(-1, 1)
+	}
+	if tile.VerticalFlip {
+		geoM.Scale(1, -1)
+	}
+	geoM.Translate(halfW, halfH)
+
+	// Tiles taller than the map's tiles are aligned to the bottom of their cell.
+	offsetX := 0.0
+	offsetY := float64(mapTileHeight - tileset.TileHeight)
+	if tileset.TileOffset != nil {
+		offsetX += float64(tileset.TileOffset.X)
+		offsetY += float64(tileset.TileOffset.Y)
+	}
+	geoM.Translate(float64(x)+offsetX, float64(y)+offsetY)
+}
+
+// loadTilesetImage loads the image of a single image tileset.
+func loadTilesetImage(tileset *tiled.Tileset, loadImage imageLoader) (*ebiten.Image, error) {
+	if tileset.Image == nil {
+		return nil, fmt.Errorf("map: tileset %q has no image", tileset.Name)
+	}
+	return loadImage(tileset.GetFileFullPath(tileset.Image.Source))
+}
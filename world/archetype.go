@@ -0,0 +1,111 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/anilkonac/magrix/asset"
+)
+
+const defaultEnemyArchetype = "robot"
+
+// enemyArchetypes are the kinds of enemies by their names, read from the enemies data file.
+var enemyArchetypes map[string]*EnemyArchetype
+
+func init() {
+	var err error
+	enemyArchetypes, err = parseEnemyArchetypes(asset.Bytes(asset.DataEnemies))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// EnemyArchetype is a kind of enemy. Enemy objects choose theirs with the archetype property.
+type EnemyArchetype struct {
+	Name   string      `json:"-"`
+	Sprite EnemySprite `json:"sprite"`
+
+	// Physics
+	Width          float64 `json:"width"`  // tiles
+	Height         float64 `json:"height"` // tiles
+	Round          bool    `json:"round"`  // a circle as wide as the enemy instead of a box
+	Mass           float64 `json:"mass"`
+	Moment         float64 `json:"moment"` // 0 for enemies that never tip over
+	Friction       float64 `json:"friction"`
+	Flying         bool    `json:"flying"` // hovers at its height while alive
+	Metal          bool    `json:"metal"`
+	MagnetStrength float64 `json:"magnetStrength"`
+
+	// Damage
+	Health           float64 `json:"health"`
+	DamageThreshold  float64 `json:"damageThreshold"`
+	DamagePerImpulse float64 `json:"damagePerImpulse"`
+	Armor            float64 `json:"armor"`      // portion of all damage stopped by its shield
+	ExplodeSec       float64 `json:"explodeSec"` // after it dies
+
+	Weapon *EnemyWeapon `json:"weapon"` // nil for enemies that don't fire
+	AI     EnemyAI      `json:"ai"`
+}
+
+// EnemySprite is the animation of an enemy archetype, a row of frames in an image of the assets.
+type EnemySprite struct {
+	Image       string     `json:"image"`
+	Frames      string     `json:"frames"` // columns of the frames, like 1-4
+	FrameWidth  int        `json:"frameWidth"`
+	FrameHeight int        `json:"frameHeight"`
+	FrameMs     int        `json:"frameMs"`
+	OriginX     float64    `json:"originX"`
+	OriginY     float64    `json:"originY"`
+	Scale       float64    `json:"scale"`
+	Color       [4]float64 `json:"color"` // multiplied with the image
+}
+
+// EnemyWeapon is the rocket launcher of an enemy archetype.
+type EnemyWeapon struct {
+	Projectile     string     `json:"projectile"` // kind of rockets, one of rocket, homing, grenade, plasma or laser
+	CooldownSec    float64    `json:"cooldownSec"`
+	Range          float64    `json:"range"`  // of the line of fire
+	Radius         float64    `json:"radius"` // the enemy fires when the player is this close to its line of fire
+	Muzzle         [2]float64 `json:"muzzle"` // where rockets spawn relative to the enemy turned right
+	RocketVelocity float64    `json:"rocketVelocity"`
+	RocketDamage   float64    `json:"rocketDamage"`
+	kind           RocketKind
+}
+
+// EnemyAI is the behavior of an enemy archetype.
+type EnemyAI struct {
+	PatrolSpeed      float64 `json:"patrolSpeed"`
+	ChaseSpeed       float64 `json:"chaseSpeed"`
+	ChaseMinDistance float64 `json:"chaseMinDistance"` // the enemy keeps this far from the player to fire
+	VisionAngle      float64 `json:"visionAngle"`      // degrees
+	VisionRange      float64 `json:"visionRange"`
+	Stationary       bool    `json:"stationary"`     // never walks, like a turret
+	Flees            bool    `json:"flees"`          // runs away while the gun pulls it
+	KamikazeDamage   float64 `json:"kamikazeDamage"` // dealt to the player by running into it, blowing itself up
+}
+
+func parseEnemyArchetypes(data []byte) (map[string]*EnemyArchetype, error) {
+	var archetypes map[string]*EnemyArchetype
+	if err := json.Unmarshal(data, &archetypes); err != nil {
+		return nil, fmt.Errorf("enemies: %w", err)
+	}
+	if archetypes[defaultEnemyArchetype] == nil {
+		return nil, fmt.Errorf("enemies: no %q archetype", defaultEnemyArchetype)
+	}
+	for name, a := range archetypes {
+		if a.Width <= 0 || a.Height <= 0 || a.Mass <= 0 {
+			return nil, fmt.Errorf("enemies: archetype %q needs a positive width, height and mass", name)
+		}
+		if a.Weapon != nil {
+			kind, ok := rocketKindNames[a.Weapon.Projectile]
+			if !ok {
+				return nil, fmt.Errorf("enemies: archetype %q has unknown projectile %q", name, a.Weapon.Projectile)
+			}
+			a.Weapon.kind = kind
+		}
+		a.Name = name
+	}
+	return archetypes, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lafriks/go-tiled"
+)
+
+func TestEnemyArchetypeOfObject(t *testing.T) {
+	tmx := withEnemies(testMap(""), `
+  <object id="10" type="enemy" x="100" y="180"/>
+  <object id="11" type="enemy" x="200" y="120">
+   <properties>
+    <property name="archetype" value="drone"/>
+   </properties>
+  </object>`)
+	w := loadTestWorld(t, tmx)
+
+	if name := w.Enemies[0].Archetype.Name; name != defaultEnemyArchetype {
+		t.Errorf("enemy without an archetype is a %q", name)
+	}
+	drone := w.Enemies[1]
+	if drone.Archetype.Name != "drone" || !drone.Archetype.Flying {
+		t.Fatalf("enemy is a %q, not a flying drone", drone.Archetype.Name)
+	}
+
+	// It keeps its height while alive
+	stepWorld(w, Input{}, ticks(2))
+	if y := drone.Body.Position().Y; y > 120+TileLength {
+		t.Errorf("drone fell from y=120 to %.1f", y)
+	}
+}
+
+func TestUnknownEnemyArchetype(t *testing.T) {
+	tmx := withEnemies(testMap(""), `
+  <object id="10" type="enemy" x="100" y="180">
+   <properties>
+    <property name="archetype" value="dragon"/>
+   </properties>
+  </object>`)
+	gameMap, err := tiled.LoadReader("", strings.NewReader(tmx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(gameMap); err == nil {
+		t.Fatal("enemy with an unknown archetype is loaded")
+	}
+}
+
+func TestParseEnemyArchetypes(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{"invalid JSON", `{`},
+		{"no default", `{"brute": {"width": 1, "height": 1, "mass": 1}}`},
+		{"no mass", `{"robot": {"width": 1, "height": 1}}`},
+		{"unknown projectile", `{"robot": {"width": 1, "height": 1, "mass": 1, "weapon": {"projectile": "banana"}}}`},
+	} {
+		if _, err := parseEnemyArchetypes([]byte(tc.data)); err == nil {
+			t.Errorf("%s: no error", tc.name)
+		}
+	}
+
+	archetypes, err := parseEnemyArchetypes([]byte(`{"robot": {"width": 1, "height": 1, "mass": 1, "weapon": {"projectile": "grenade"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if robot := archetypes["robot"]; robot.Name != "robot" || robot.Weapon.kind != RocketKindGrenade {
+		t.Fatalf("archetype is %+v", robot)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+func init() {
+	registerEntity(ObjectTypeButton, newButton)
+}
+
+// Button completes the level when the player presses it, triggering its targets too.
+type Button struct {
+	entityBase
+	Triggered bool
+	shapes    []*cp.Shape
+	targets   []uint32
+}
+
+func newButton(obj *tiled.Object, w *World) Entity {
+	return &Button{
+		entityBase: newEntityBase(obj),
+		shapes:     w.addWallShapes(obj),
+		targets:    objectTargets(obj),
+	}
+}
+
+func (b *Button) interact(w *World) {
+	b.trigger(w)
+}
+
+func (b *Button) trigger(w *World) {
+	if b.Triggered {
+		return
+	}
+	b.Triggered = true
+	w.Completed = true
+	w.triggerTargets(b.targets)
+}
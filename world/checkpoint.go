@@ -0,0 +1,88 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+func init() {
+	registerEntity(ObjectTypeCheckpoint, newCheckpoint)
+}
+
+// Checkpoint is an area of the map where the player respawns after dying once it has been reached.
+type Checkpoint struct {
+	entityBase
+	shape *cp.Shape
+}
+
+func newCheckpoint(obj *tiled.Object, w *World) Entity {
+	c := &Checkpoint{entityBase: newEntityBase(obj)}
+
+	c.shape = w.space.AddShape(cp.NewBox2(w.space.StaticBody, cp.NewBBForExtents(c.pos, obj.Width/2.0, obj.Height/2.0), 0))
+	c.shape.SetSensor(true)
+	c.shape.SetCollisionType(collisionTypeSensor)
+	c.shape.SetFilter(filterSensor)
+	c.shape.UserData = c
+
+	return c
+}
+
+// sense makes the checkpoint the last one reached when the player enters it. Entering it again refreshes the progress
+// kept, like walls removed since.
+func (c *Checkpoint) sense(w *World) {
+	if w.Player.NumLives <= 0 {
+		return
+	}
+	w.checkpoint = c
+	w.emit(EventCheckpoint, c.pos)
+}
+
+// persistent is an entity whose state is kept in the progress.
+type persistent interface {
+	saved() bool // reports whether the state is to be kept
+	restore(w *World)
+}
+
+// Progress is what is kept of a level when the player respawns at a checkpoint.
+type Progress struct {
+	Checkpoint uint32   // ID of the last checkpoint reached
+	NumLives   int      // lives of the player when the checkpoint was reached
+	Entities   []uint32 // IDs of the entities whose states are kept, like triggered terminals and removed walls
+}
+
+// Progress returns the progress of the player in the level.
+func (w *World) Progress() Progress {
+	var progress Progress
+	if w.checkpoint != nil {
+		progress.Checkpoint = w.checkpoint.ID()
+	}
+	progress.NumLives = w.Player.NumLives
+
+	for _, entity := range w.Entities {
+		if p, ok := entity.(persistent); ok && p.saved() {
+			progress.Entities = append(progress.Entities, entity.ID())
+		}
+	}
+
+	return progress
+}
+
+// Restore brings a newly built world to the progress, respawning the player at its checkpoint.
+// Parts of the progress that the map no longer has are ignored.
+func (w *World) Restore(progress Progress) {
+	w.Player.NumLives = progress.NumLives
+
+	for _, id := range progress.Entities {
+		if p, ok := w.entityByID[id].(persistent); ok {
+			p.restore(w)
+		}
+	}
+
+	if checkpoint, ok := w.entityByID[progress.Checkpoint].(*Checkpoint); ok {
+		w.checkpoint = checkpoint
+		w.Player.Body.SetPosition(checkpoint.pos)
+		w.Player.Pos = checkpoint.pos
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"reflect"
+	"testing"
+)
+
+const checkpointObjects = `
+  <object id="10" type="checkpoint" x="200" y="150" width="32" height="50"/>
+  <object id="11" type="terminal" x="80" y="184" width="16" height="16">
+   <properties>
+    <property name="delay" type="float" value="0.5"/>
+    <property name="target" type="object" value="12"/>
+   </properties>
+  </object>
+  <object id="12" type="electricWall" x="150" y="100" width="8" height="100"/>
+`
+
+func TestProgressRestore(t *testing.T) {
+	w := loadTestWorld(t, testMap(checkpointObjects))
+	terminal := w.entityByID[11].(*Terminal)
+	wall := w.entityByID[12].(*ElectricWall)
+
+	terminal.interact(w)
+	stepWorld(w, Input{}, ticks(terminal.delaySec)+4)
+	if !wall.Removed {
+		t.Fatal("wall isn't removed by the terminal")
+	}
+
+	// Reach the checkpoint behind the wall
+	w.Player.Body.SetPosition(w.entityByID[10].Position())
+	stepWorld(w, Input{}, 2)
+	w.Player.NumLives = 2
+	progress := w.Progress()
+	if progress.Checkpoint != 10 || progress.NumLives != 2 || len(progress.Entities) != 2 {
+		t.Fatalf("progress is %+v", progress)
+	}
+
+	restored := loadTestWorld(t, testMap(checkpointObjects))
+	restored.Restore(progress)
+	if !restored.entityByID[11].(*Terminal).Triggered {
+		t.Error("terminal isn't triggered after restoring")
+	}
+	restoredWall := restored.entityByID[12].(*ElectricWall)
+	if !restoredWall.Removed || restored.space.ContainsShape(restoredWall.shapes[0]) {
+		t.Error("wall isn't removed after restoring")
+	}
+	if restored.Player.NumLives != 2 {
+		t.Errorf("player has %d lives after restoring", restored.Player.NumLives)
+	}
+	if pos := restored.entityByID[10].Position(); restored.Player.Pos != pos {
+		t.Errorf("player is at %v after restoring, not at the checkpoint at %v", restored.Player.Pos, pos)
+	}
+}
+
+func TestRestoreIgnoresMissingEntities(t *testing.T) {
+	w := loadTestWorld(t, testMap(""))
+	start := w.Player.Pos
+	w.Restore(Progress{Checkpoint: 10, NumLives: 3, Entities: []uint32{11, 12}})
+	if w.Player.Pos != start || w.checkpoint != nil {
+		t.Fatalf("player moved to %v by a checkpoint the map doesn't have", w.Player.Pos)
+	}
+}
+
+func TestCheckpointRefreshesProgress(t *testing.T) {
+	w := loadTestWorld(t, testMap(checkpointObjects))
+	checkpoint := w.entityByID[10]
+	terminal := w.entityByID[11].(*Terminal)
+
+	// Through the checkpoint before the wall is removed
+	w.Player.Body.SetPosition(checkpoint.Position())
+	if _, ok := stepUntilEvent(w, EventCheckpoint, 0.5); !ok {
+		t.Fatal("checkpoint isn't reached")
+	}
+	if progress := w.Progress(); len(progress.Entities) != 0 {
+		t.Fatalf("progress keeps %v before anything is triggered", progress.Entities)
+	}
+
+	// Back to the terminal and through the checkpoint again
+	w.Player.Body.SetPosition(terminal.Position())
+	terminal.interact(w)
+	stepWorld(w, Input{}, ticks(terminal.delaySec)+4)
+	w.Player.Body.SetPosition(checkpoint.Position())
+	if _, ok := stepUntilEvent(w, EventCheckpoint, 0.5); !ok {
+		t.Fatal("checkpoint entered again isn't reached")
+	}
+	if progress := w.Progress(); !reflect.DeepEqual(progress.Entities, []uint32{11, 12}) {
+		t.Fatalf("progress keeps %v, not the terminal and the wall", progress.Entities)
+	}
+}
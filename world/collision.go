@@ -0,0 +1,86 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import "github.com/jakecoffman/cp"
+
+// Collision types of the shapes, telling the space which collision handlers to call
+const (
+	collisionTypeWall cp.CollisionType = iota + 1
+	collisionTypePlayer
+	collisionTypeEnemy
+	collisionTypeRocket
+	collisionTypeSensor
+)
+
+// Categories of the shapes for shape filters. Two shapes collide only if each one's mask has the other's category.
+const (
+	categoryWall uint = 1 << iota
+	categoryPlayer
+	categoryEnemy
+	categoryRocket
+	categorySensor
+	categoryCrate
+)
+
+var (
+	filterWall   = cp.NewShapeFilter(cp.NO_GROUP, categoryWall, cp.ALL_CATEGORIES)
+	filterPlayer = cp.NewShapeFilter(cp.NO_GROUP, categoryPlayer, cp.ALL_CATEGORIES)
+	filterCrate  = cp.NewShapeFilter(cp.NO_GROUP, categoryCrate, cp.ALL_CATEGORIES)
+	filterSensor = cp.NewShapeFilter(cp.NO_GROUP, categorySensor, categoryPlayer) // only the player triggers sensors
+	filterPlate  = cp.NewShapeFilter(cp.NO_GROUP, categorySensor, categoryPlayer|categoryEnemy|categoryCrate)
+	// The gun ray hits everything but the player and sensors
+	filterGunRay = cp.NewShapeFilter(cp.NO_GROUP, cp.ALL_CATEGORIES, cp.ALL_CATEGORIES&^(categoryPlayer|categorySensor))
+)
+
+// filterEnemy returns the filter of an enemy and its rockets. Shapes of the same group don't collide,
+// so an enemy isn't hit by its own rockets.
+func filterEnemy(group uint) cp.ShapeFilter {
+	return cp.NewShapeFilter(group, categoryEnemy, cp.ALL_CATEGORIES)
+}
+
+func filterRocket(group uint) cp.ShapeFilter {
+	return cp.NewShapeFilter(group, categoryRocket, cp.ALL_CATEGORIES)
+}
+
+// sensor is an entity with a sensor shape that the player can pass through.
+type sensor interface {
+	sense(w *World) // called when the player enters the shape
+}
+
+// addCollisionHandlers makes the space report rocket hits, impacts on the player and enemies, and sensors entered by the player.
+// Handlers are called during the step of the space, so they only record what happened; shapes are removed after the step.
+func (w *World) addCollisionHandlers() {
+	handlerRocket := w.space.NewWildcardCollisionHandler(collisionTypeRocket)
+	handlerRocket.BeginFunc = func(arb *cp.Arbiter, _ *cp.Space, _ interface{}) bool {
+		shapeRocket, shapeOther := arb.Shapes()
+		rocket := shapeRocket.UserData.(*Rocket)
+		if rocket.hitBody == nil && !rocket.bounces(shapeOther) {
+			rocket.hitBody = shapeOther.Body()
+		}
+		return true
+	}
+
+	handlerEnemy := w.space.NewWildcardCollisionHandler(collisionTypeEnemy)
+	handlerEnemy.PostSolveFunc = func(arb *cp.Arbiter, _ *cp.Space, _ interface{}) {
+		if !arb.IsFirstContact() {
+			return
+		}
+		shapeEnemy, _ := arb.Shapes()
+		shapeEnemy.UserData.(*Enemy).impact(arb.TotalImpulse().Length())
+	}
+
+	handlerPlayer := w.space.NewWildcardCollisionHandler(collisionTypePlayer)
+	handlerPlayer.PostSolveFunc = func(arb *cp.Arbiter, _ *cp.Space, _ interface{}) {
+		if arb.IsFirstContact() {
+			w.Player.impact(arb.TotalImpulse().Length())
+		}
+	}
+
+	handlerSensor := w.space.NewCollisionHandler(collisionTypePlayer, collisionTypeSensor)
+	handlerSensor.BeginFunc = func(arb *cp.Arbiter, _ *cp.Space, _ interface{}) bool {
+		_, shapeSensor := arb.Shapes()
+		shapeSensor.UserData.(sensor).sense(w)
+		return true
+	}
+}
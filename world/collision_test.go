@@ -0,0 +1,125 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp"
+)
+
+// fireRocket adds a rocket of the kind flying from the position at the angle.
+func fireRocket(w *World, pos cp.Vector, angle float64, kind RocketKind, group uint) *Rocket {
+	weapon := enemyArchetypes[defaultEnemyArchetype].Weapon
+	rocket := newRocket(pos, angle, kind, weapon.RocketVelocity, weapon.RocketDamage, w.space, group)
+	w.addMagnet(rocket.shape, rocketKinds[kind].magnet)
+	w.rocketManager.rockets = append(w.rocketManager.rockets, rocket)
+	return rocket
+}
+
+// stepUntilEvent steps the world for the seconds at most, until it emits an event of the kind.
+func stepUntilEvent(w *World, kind EventKind, sec float64) (Event, bool) {
+	for i := 0; i < ticks(sec); i++ {
+		w.Step(&Input{})
+		for _, event := range w.Events {
+			if event.Kind == kind {
+				return event, true
+			}
+		}
+	}
+	return Event{}, false
+}
+
+func TestRocketHitsWall(t *testing.T) {
+	w := loadTestWorld(t, testMap(""))
+	fireRocket(w, cp.Vector{X: 160, Y: 150}, math.Pi/2, RocketKindRocket, 0) // down to the floor
+
+	event, ok := stepUntilEvent(w, EventRocketHit, 1)
+	if !ok {
+		t.Fatal("rocket didn't hit the floor")
+	}
+	if math.Abs(event.Pos.Y-200) > rocketWidth {
+		t.Errorf("rocket hit at %v, not on the floor at y=200", event.Pos)
+	}
+	if len(w.Rockets()) != 0 {
+		t.Errorf("%d rockets are left after the hit", len(w.Rockets()))
+	}
+}
+
+func TestRocketHitsPlayer(t *testing.T) {
+	w := loadTestWorld(t, testMap(""))
+	stepWorld(w, Input{}, ticks(0.5)) // land
+	lives := w.Player.NumLives
+	fireRocket(w, w.Player.Pos.Add(cp.Vector{X: 80}), math.Pi, RocketKindRocket, 0)
+
+	if _, ok := stepUntilEvent(w, EventRocketHit, 1); !ok {
+		t.Fatal("rocket didn't hit the player")
+	}
+	if w.Player.NumLives != lives-1 {
+		t.Fatalf("player has %d lives after the hit, not %d", w.Player.NumLives, lives-1)
+	}
+}
+
+func TestEnemyIgnoresOwnRockets(t *testing.T) {
+	tmx := withEnemies(testMap(""), `
+  <object id="10" type="enemy" x="160" y="176"/>`)
+	w := loadTestWorld(t, tmx)
+	enemy := w.Enemies[0]
+
+	// Its own rocket passes through it, flying away
+	fireRocket(w, enemy.Body.Position(), 0, RocketKindRocket, enemy.group)
+	stepWorld(w, Input{}, ticks(0.5))
+	if !enemy.IsAlive {
+		t.Fatal("enemy is killed by its own rocket")
+	}
+
+	// Others' don't
+	fireRocket(w, enemy.Body.Position().Add(cp.Vector{X: -40}), 0, RocketKindRocket, enemy.group+1)
+	stepWorld(w, Input{}, ticks(0.5))
+	if enemy.IsAlive {
+		t.Fatal("enemy isn't killed by the rocket of another")
+	}
+}
+
+func TestEnemyFallImpact(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		y     float64
+		alive bool
+	}{
+		{"standing", 176, true},
+		{"falling from high", -600, false},
+	} {
+		tmx := withEnemies(testMap(""), `
+  <object id="10" type="enemy" x="160" y="`+fmt.Sprint(tc.y)+`"/>`)
+		w := loadTestWorld(t, tmx)
+		stepWorld(w, Input{}, ticks(3))
+		if enemy := w.Enemies[0]; enemy.IsAlive != tc.alive {
+			t.Errorf("%s: enemy alive is %v", tc.name, enemy.IsAlive)
+		}
+	}
+}
+
+func TestCheckpointSensor(t *testing.T) {
+	w := loadTestWorld(t, testMap(`
+  <object id="10" type="checkpoint" x="100" y="150" width="32" height="50"/>`))
+	checkpoint := w.entityByID[10].(*Checkpoint)
+
+	numEvents := 0
+	for i := 0; i < ticks(3); i++ {
+		w.Step(&Input{Right: true})
+		for _, event := range w.Events {
+			if event.Kind == EventCheckpoint {
+				numEvents++
+			}
+		}
+	}
+	if numEvents != 1 || w.checkpoint != checkpoint {
+		t.Fatalf("%d checkpoint events, last checkpoint %v", numEvents, w.checkpoint)
+	}
+	if w.Player.Pos.X < 132 {
+		t.Fatalf("player at %v is blocked by the checkpoint", w.Player.Pos)
+	}
+}
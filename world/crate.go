@@ -0,0 +1,51 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+const (
+	crateMass       = 1.5
+	crateFriction   = 0.7
+	crateElasticity = 0
+)
+
+func init() {
+	registerEntity(ObjectTypeCrate, newCrate)
+}
+
+// Crate is a box the player can move around with the gun. Crates are metal unless the map says otherwise.
+type Crate struct {
+	entityBase
+	Body   *cp.Body
+	Width  float64
+	Height float64
+	shape  *cp.Shape
+}
+
+func newCrate(obj *tiled.Object, w *World) Entity {
+	c := &Crate{
+		entityBase: newEntityBase(obj),
+		Width:      obj.Width,
+		Height:     obj.Height,
+	}
+
+	mass := floatProperty(obj, propertyMass, crateMass)
+	c.Body = w.space.AddBody(cp.NewBody(mass, cp.MomentForBox(mass, obj.Width, obj.Height)))
+	c.Body.SetPosition(c.pos)
+
+	c.shape = w.space.AddShape(cp.NewBox(c.Body, obj.Width, obj.Height, 0))
+	c.shape.SetFriction(crateFriction)
+	c.shape.SetElasticity(crateElasticity)
+	c.shape.SetFilter(filterCrate)
+	w.addMagnet(c.shape, newMagnet(obj, true))
+
+	return c
+}
+
+func (c *Crate) Position() cp.Vector {
+	return c.Body.Position()
+}
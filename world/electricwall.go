@@ -0,0 +1,78 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+func init() {
+	registerEntity(ObjectTypeElectricWall, newElectricWall)
+}
+
+// ElectricWall blocks the way until it is triggered.
+type ElectricWall struct {
+	entityBase
+	Color   string // one of the ColorName values
+	Removed bool
+	Held    bool // removed while a pressure plate holds it
+	shapes  []*cp.Shape
+}
+
+func newElectricWall(obj *tiled.Object, w *World) Entity {
+	shapes := w.addWallShapes(obj)
+	for _, shape := range shapes {
+		shape.SetElasticity(wallElasticity)
+		shape.SetFriction(wallFriction)
+	}
+
+	return &ElectricWall{
+		entityBase: newEntityBase(obj),
+		Color:      obj.Properties.GetString(propertyColor),
+		shapes:     shapes,
+	}
+}
+
+// trigger removes the wall.
+func (e *ElectricWall) trigger(w *World) {
+	if e.Removed {
+		return
+	}
+	if !e.Held {
+		e.removeShapes(w)
+	}
+	e.Removed = true
+}
+
+// hold removes the wall until it is released.
+func (e *ElectricWall) hold(w *World, held bool) {
+	if held == e.Held {
+		return
+	}
+	e.Held = held
+	if e.Removed {
+		return
+	}
+	if held {
+		e.removeShapes(w)
+	} else {
+		for _, shape := range e.shapes {
+			w.space.AddShape(shape)
+		}
+	}
+}
+
+func (e *ElectricWall) removeShapes(w *World) {
+	for _, shape := range e.shapes {
+		w.space.RemoveShape(shape)
+	}
+}
+
+func (e *ElectricWall) saved() bool {
+	return e.Removed
+}
+
+func (e *ElectricWall) restore(w *World) {
+	e.trigger(w)
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+
+	"github.com/jakecoffman/cp"
+)
+
+const (
+	enemyElasticity = 0.0
+	enemyWreckSec   = 2.0 // from the explosion of a dead enemy until it is removed
+)
+
+type Enemy struct {
+	Body              *cp.Body
+	Archetype         *EnemyArchetype
+	TurnedLeft        bool
+	IsAlive           bool
+	Removed           bool // the wreck is removed from the space
+	Health            Health
+	State             EnemyState
+	Alertness         float64 // from 0 to 1, when the enemy notices the player
+	shape             *cp.Shape
+	vision            vision
+	fireRay           [2]cp.Vector
+	attackCooldownSec float32
+	group             uint // shape filter group of the enemy and its rockets
+
+	// AI
+	stationary   bool        // never walks, like a turret
+	waypoints    []cp.Vector // of the patrol
+	iWaypoint    int
+	waypointStep int // 1 or -1 along the waypoints
+	seesPlayer   bool
+	lastKnownPos cp.Vector // of the player
+	stateSec     float64   // left in the current state
+	searched     bool      // reached the last known position of the player
+	fleeSec      float64
+	speed        float64 // horizontal, set by the AI every update
+	hoverY       float64 // height flying enemies keep
+}
+
+func newEnemy(pos cp.Vector, space *cp.Space, archetype *EnemyArchetype, turnedLeft bool, health Health, group uint) *Enemy {
+	enemy := &Enemy{
+		Archetype:         archetype,
+		attackCooldownSec: 0,
+		TurnedLeft:        turnedLeft,
+		IsAlive:           true,
+		Health:            health,
+		group:             group,
+		vision:            newVision(archetype.AI.VisionAngle, archetype.AI.VisionRange),
+		stationary:        archetype.AI.Stationary,
+		waypointStep:      1,
+		hoverY:            pos.Y,
+	}
+
+	moment := archetype.Moment
+	if moment <= 0 {
+		moment = cp.INFINITY
+	}
+	body := cp.NewBody(archetype.Mass, moment)
+	body.SetPosition(cp.Vector{X: pos.X, Y: pos.Y})
+	body.SetVelocityUpdateFunc(enemy.updateVelocity)
+	enemy.Body = body
+
+	width, height := archetype.Width*TileLength, archetype.Height*TileLength
+	if archetype.Round {
+		enemy.shape = cp.NewCircle(enemy.Body, width/2.0, cp.Vector{})
+	} else {
+		enemy.shape = cp.NewBox(enemy.Body, width, height, 0)
+	}
+	enemy.shape.SetElasticity(enemyElasticity)
+	enemy.shape.SetFriction(archetype.Friction)
+	enemy.shape.SetCollisionType(collisionTypeEnemy)
+	enemy.shape.SetFilter(filterEnemy(group))
+	enemy.shape.UserData = enemy
+
+	space.AddBody(enemy.Body)
+	space.AddShape(enemy.shape)
+
+	return enemy
+}
+
+// update returns true when the enemy has died of its damage.
+func (e *Enemy) update(w *World) (hasDied bool) {
+	if e.IsAlive {
+		e.ram(w)
+		if e.Health.Points <= 0 {
+			hasDied = true
+			e.IsAlive = false
+			e.shape.SetSurfaceV(cp.Vector{})
+		} else {
+			e.think(w)
+		}
+
+		weapon := e.Archetype.Weapon
+		if weapon == nil {
+			return hasDied
+		}
+		pos := e.Body.Position()
+
+		// Line of fire
+		angle := e.Body.Angle()
+		turnMult := 1.0
+		if e.TurnedLeft {
+			turnMult = -1.0
+		}
+		e.fireRay[0] = pos
+		e.fireRay[1] = e.fireRay[0].Add(
+			cp.Vector{
+				X: weapon.Range * turnMult * math.Cos(angle), Y: weapon.Range * math.Sin(angle),
+			},
+		)
+	}
+
+	return hasDied
+}
+
+// damage takes the points, less what the armor stops, from the health of the enemy.
+func (e *Enemy) damage(points float64) {
+	e.Health.damage(points * (1 - e.Archetype.Armor))
+}
+
+// impact is called by the collision handler with the impulse of the first contact of a collision.
+func (e *Enemy) impact(impulse float64) {
+	if e.IsAlive {
+		e.damage(e.Health.impactDamage(impulse))
+	}
+}
+
+// updateVelocity cancels the gravity of flying enemies until they die.
+func (e *Enemy) updateVelocity(body *cp.Body, gravity cp.Vector, damping, dt float64) {
+	if e.Archetype.Flying && e.IsAlive {
+		gravity = cp.Vector{}
+	}
+	body.UpdateVelocity(gravity, damping, dt)
+}
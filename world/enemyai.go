@@ -0,0 +1,228 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+
+	"github.com/jakecoffman/cp"
+)
+
+// EnemyState is what an enemy is doing.
+type EnemyState uint8
+
+const (
+	EnemyStateIdle   EnemyState = iota // standing, looking the way it turned
+	EnemyStatePatrol                   // walking between the points of its path
+	EnemyStateChase                    // going after the player it sees
+	EnemyStateSearch                   // going to where it last saw the player
+	EnemyStateFlee                     // running away from the player pulling it with the gun
+)
+
+const (
+	enemySearchSec          = 4.0
+	enemyLookAroundSec      = 1.0 // between turns while searching
+	enemyFleeSec            = 1.5 // after the gun lets go
+	enemyWaypointTolerance  = TileLength / 4.0
+	enemyUprightTolerance   = 0.3 // radians
+	enemyProbeLength        = TileLength / 2.0
+	enemyLedgeProbeDistance = TileLength // drops deeper than this are ledges
+	enemyFlyControl         = 0.05       // portion of the velocity of flying enemies corrected in each update
+	enemyHoverStiffness     = 4.0        // vertical speed of flying enemies per pixel off their height
+)
+
+// Walls, platforms and crates; what an enemy walks on and bumps into
+var filterEnemyProbe = cp.NewShapeFilter(cp.NO_GROUP, cp.ALL_CATEGORIES, categoryWall|categoryCrate)
+
+// think runs the state machine of the enemy and moves it. Vision is updated before it by the world's ray cast.
+func (e *Enemy) think(w *World) {
+	ai := &e.Archetype.AI
+	e.speed = 0
+	if e.seesPlayer {
+		e.lastKnownPos = w.Player.Pos
+	}
+	if e.fleeSec > 0 {
+		e.fleeSec -= DeltaTimeSec
+	}
+
+	switch e.State {
+	case EnemyStateIdle, EnemyStatePatrol:
+		if e.seesPlayer {
+			e.State = EnemyStateChase
+		} else if e.State == EnemyStatePatrol {
+			e.patrol(w)
+		}
+
+	case EnemyStateChase:
+		if !e.seesPlayer {
+			e.search()
+			break
+		}
+		toPlayer := w.Player.Pos.X - e.Body.Position().X
+		if math.Abs(toPlayer) > ai.ChaseMinDistance {
+			e.walk(w, toPlayer, ai.ChaseSpeed)
+		} else {
+			e.face(toPlayer)
+			e.stop()
+		}
+
+	case EnemyStateSearch:
+		if e.seesPlayer {
+			e.State = EnemyStateChase
+			break
+		}
+		e.stateSec -= DeltaTimeSec
+		if e.stateSec <= 0 {
+			e.rest()
+			break
+		}
+		toLastKnown := e.lastKnownPos.X - e.Body.Position().X
+		if math.Abs(toLastKnown) > enemyWaypointTolerance && !e.searched {
+			e.searched = e.walk(w, toLastKnown, ai.ChaseSpeed)
+		} else {
+			// Look around
+			e.searched = true
+			e.stop()
+			if math.Mod(e.stateSec, enemyLookAroundSec) < DeltaTimeSec {
+				e.TurnedLeft = !e.TurnedLeft
+			}
+		}
+
+	case EnemyStateFlee:
+		if e.fleeSec <= 0 {
+			e.lastKnownPos = w.Player.Pos
+			e.search()
+			break
+		}
+		e.walk(w, e.Body.Position().X-w.Player.Pos.X, ai.ChaseSpeed)
+	}
+
+	if e.fleeSec > 0 && e.State != EnemyStateFlee && ai.Flees {
+		e.State = EnemyStateFlee
+	}
+	e.drive()
+}
+
+// ram blows up an enemy made to run into the player, like a kamikaze roller, when it touches the player.
+func (e *Enemy) ram(w *World) {
+	damage := e.Archetype.AI.KamikazeDamage
+	if damage <= 0 || w.Player.NumLives <= 0 {
+		return
+	}
+	touches := false
+	e.Body.EachArbiter(func(arb *cp.Arbiter) {
+		a, b := arb.Bodies()
+		touches = touches || a == w.Player.Body || b == w.Player.Body
+	})
+	if touches {
+		w.Player.damage(damage)
+		e.Health.Points = 0
+	}
+}
+
+// pull is called while the player's gun pulls or pushes the enemy.
+func (e *Enemy) pull() {
+	e.fleeSec = enemyFleeSec
+	e.alert()
+}
+
+func (e *Enemy) search() {
+	e.State = EnemyStateSearch
+	e.stateSec = enemySearchSec
+	e.searched = false
+}
+
+// rest makes the enemy go back to what it did before it saw the player.
+func (e *Enemy) rest() {
+	e.stop()
+	if len(e.waypoints) > 0 {
+		e.State = EnemyStatePatrol
+	} else {
+		e.State = EnemyStateIdle
+	}
+}
+
+// patrol walks the enemy to its next waypoint, back and forth along its path.
+func (e *Enemy) patrol(w *World) {
+	toWaypoint := e.waypoints[e.iWaypoint].X - e.Body.Position().X
+	if math.Abs(toWaypoint) > enemyWaypointTolerance && !e.walk(w, toWaypoint, e.Archetype.AI.PatrolSpeed) {
+		return
+	}
+
+	// Reached or blocked, head to the next one
+	if e.iWaypoint+e.waypointStep < 0 || e.iWaypoint+e.waypointStep >= len(e.waypoints) {
+		e.waypointStep = -e.waypointStep
+	}
+	e.iWaypoint += e.waypointStep
+	if e.iWaypoint < 0 || e.iWaypoint >= len(e.waypoints) {
+		e.iWaypoint = 0 // single point path
+	}
+}
+
+func (e *Enemy) face(direction float64) {
+	if direction != 0 {
+		e.TurnedLeft = direction < 0
+	}
+}
+
+// walk moves the enemy along the direction. It returns true if the enemy is blocked by a wall or a ledge.
+// Enemies can't walk when they are in the air or knocked over, but flying ones don't mind ledges.
+func (e *Enemy) walk(w *World, direction, speed float64) (blocked bool) {
+	e.face(direction)
+	flying := e.Archetype.Flying
+	if e.stationary || (!flying && (!e.isUpright() || !e.probe(w, cp.Vector{Y: 1}, enemyProbeLength))) {
+		e.stop()
+		return e.stationary
+	}
+
+	forward := cp.Vector{X: 1}
+	if e.TurnedLeft {
+		forward.X = -1
+	}
+	if e.probe(w, forward, enemyProbeLength) || (!flying && !e.probeLedge(w, forward)) {
+		e.stop()
+		return true
+	}
+
+	e.speed = forward.X * speed
+	return false
+}
+
+func (e *Enemy) stop() {
+	e.speed = 0
+}
+
+// drive moves walking enemies by the friction of their surface, like the player,
+// and steers flying ones towards their speed at their height.
+func (e *Enemy) drive() {
+	if !e.Archetype.Flying {
+		e.shape.SetSurfaceV(cp.Vector{X: -e.speed})
+		return
+	}
+	target := cp.Vector{X: e.speed, Y: (e.hoverY - e.Body.Position().Y) * enemyHoverStiffness}
+	e.Body.SetVelocityVector(e.Body.Velocity().Lerp(target, enemyFlyControl))
+}
+
+func (e *Enemy) isUpright() bool {
+	angle := math.Remainder(e.Body.Angle(), 2*math.Pi)
+	return math.Abs(angle) < enemyUprightTolerance
+}
+
+// probe reports whether there is anything to walk on or bump into within the distance from the enemy's side in the direction.
+func (e *Enemy) probe(w *World, direction cp.Vector, distance float64) bool {
+	halfSize := cp.Vector{X: e.Archetype.Width * TileLength / 2.0, Y: e.Archetype.Height * TileLength / 2.0}
+	start := e.Body.Position()
+	end := start.Add(cp.Vector{
+		X: direction.X * (halfSize.X + distance),
+		Y: direction.Y * (halfSize.Y + distance),
+	})
+	return w.space.SegmentQueryFirst(start, end, 0, filterEnemyProbe).Shape != nil
+}
+
+// probeLedge reports whether there is ground right in front of the enemy.
+func (e *Enemy) probeLedge(w *World, forward cp.Vector) bool {
+	pos := e.Body.Position()
+	start := pos.Add(cp.Vector{X: forward.X * (e.Archetype.Width*TileLength/2.0 + enemyProbeLength)})
+	end := start.Add(cp.Vector{Y: e.Archetype.Height*TileLength/2.0 + enemyLedgeProbeDistance})
+	return w.space.SegmentQueryFirst(start, end, 0, filterEnemyProbe).Shape != nil
+}
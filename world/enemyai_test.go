@@ -0,0 +1,90 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp"
+)
+
+// hidePlayer keeps the player out of the sight of enemies for a tick.
+func hidePlayer(w *World) {
+	w.Player.Body.SetPosition(cp.Vector{X: 40, Y: -1000})
+	w.Player.Body.SetVelocity(0, 0)
+}
+
+func TestEnemyPatrolsPath(t *testing.T) {
+	tmx := withEnemies(testMap(`
+  <object id="11" x="100" y="180"><polyline points="0,0 120,0"/></object>`), `
+  <object id="10" type="enemy" x="100" y="180">
+   <properties>
+    <property name="path" type="object" value="11"/>
+   </properties>
+  </object>`)
+	w := loadTestWorld(t, tmx)
+	enemy := w.Enemies[0]
+	if enemy.State != EnemyStatePatrol {
+		t.Fatalf("enemy with a path starts in state %d", enemy.State)
+	}
+
+	// To the end of the path and back
+	walkSec := 120 / enemy.Archetype.AI.PatrolSpeed
+	maxX, minX := 0.0, math.Inf(1)
+	for i := 0; i < ticks(walkSec+1); i++ {
+		hidePlayer(w)
+		w.Step(&Input{})
+		maxX = math.Max(maxX, enemy.Body.Position().X)
+	}
+	for i := 0; i < ticks(walkSec+1); i++ {
+		hidePlayer(w)
+		w.Step(&Input{})
+		minX = math.Min(minX, enemy.Body.Position().X)
+	}
+	if maxX < 220-enemyWaypointTolerance || maxX > 220+TileLength {
+		t.Errorf("enemy walked to x=%.1f, not to the end of its path at 220", maxX)
+	}
+	if minX > 100+enemyWaypointTolerance || minX < 100-TileLength {
+		t.Errorf("enemy walked back to x=%.1f, not to the start of its path at 100", minX)
+	}
+	if enemy.State != EnemyStatePatrol {
+		t.Errorf("enemy is in state %d after patrolling", enemy.State)
+	}
+}
+
+func TestEnemyChasesPlayer(t *testing.T) {
+	tmx := withEnemies(testMap(""), `
+  <object id="10" type="enemy" x="240" y="180">
+   <properties>
+    <property name="turnedLeft" type="bool" value="true"/>
+   </properties>
+  </object>`)
+	w := loadTestWorld(t, tmx)
+	enemy := w.Enemies[0]
+	start := enemy.Body.Position()
+
+	stepWorld(w, Input{}, ticks(2))
+	if enemy.State != EnemyStateChase {
+		t.Fatalf("enemy seeing the player is in state %d", enemy.State)
+	}
+	if pos := enemy.Body.Position(); pos.X >= start.X-TileLength {
+		t.Fatalf("enemy chasing the player on its left went from x=%.1f to %.1f", start.X, pos.X)
+	}
+
+	// Hide from it
+	hide := func(ticks int) {
+		for i := 0; i < ticks; i++ {
+			hidePlayer(w)
+			w.Step(&Input{})
+		}
+	}
+	hide(2)
+	if enemy.State != EnemyStateSearch {
+		t.Fatalf("enemy that lost the player is in state %d", enemy.State)
+	}
+	hide(ticks(enemySearchSec) + 1)
+	if enemy.State != EnemyStateIdle {
+		t.Fatalf("enemy is in state %d after searching", enemy.State)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+// Entity is an object of the map the player interacts with, like a terminal.
+// Entities are created from the Tiled objects whose types have a registered factory.
+type Entity interface {
+	ID() uint32   // ID of the Tiled object
+	Type() string // type of the Tiled object
+	Position() cp.Vector
+	update(w *World)
+	interact(w *World) // called when the player activates the entity
+}
+
+// entityFactory creates the entity of a Tiled object in the world.
+type entityFactory func(obj *tiled.Object, w *World) Entity
+
+var entityFactories = make(map[string]entityFactory)
+
+// registerEntity makes the objects of the type be created by the factory.
+// Entity kinds register themselves in init functions.
+func registerEntity(typ string, factory entityFactory) {
+	entityFactories[typ] = factory
+}
+
+// entityBase implements the parts of Entity that are the same for most entities.
+type entityBase struct {
+	id  uint32
+	typ string
+	pos cp.Vector
+}
+
+func newEntityBase(obj *tiled.Object) entityBase {
+	return entityBase{
+		id:  obj.ID,
+		typ: objectType(obj),
+		pos: cp.Vector{X: obj.X + obj.Width/2.0, Y: obj.Y + obj.Height/2.0},
+	}
+}
+
+func (e *entityBase) ID() uint32          { return e.id }
+func (e *entityBase) Type() string        { return e.typ }
+func (e *entityBase) Position() cp.Vector { return e.pos }
+func (e *entityBase) update(w *World)     {}
+func (e *entityBase) interact(w *World)   {}
+
+// addEntities creates the entities of all objects in the map that have a factory.
+func (w *World) addEntities(gameMap *tiled.Map) {
+	for _, group := range gameMap.ObjectGroups {
+		for _, obj := range group.Objects {
+			factory, ok := entityFactories[objectType(obj)]
+			if !ok {
+				continue
+			}
+
+			entity := factory(obj, w)
+			w.Entities = append(w.Entities, entity)
+			w.entityByID[obj.ID] = entity
+		}
+	}
+}
+
+// hasEntity reports whether the world has an entity of the type.
+func (w *World) hasEntity(typ string) bool {
+	for _, entity := range w.Entities {
+		if entity.Type() == typ {
+			return true
+		}
+	}
+	return false
+}
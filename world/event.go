@@ -0,0 +1,20 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import "github.com/jakecoffman/cp"
+
+type EventKind uint8
+
+const (
+	EventRocketHit     EventKind = iota // a rocket exploded hitting something
+	EventEnemyKilled                    // an enemy was destroyed
+	EventEnemyExploded                  // the wreck of an enemy exploded
+	EventCheckpoint                     // the player reached a checkpoint
+)
+
+// Event is something that happened in a step which the world doesn't show itself, like an explosion.
+type Event struct {
+	Kind EventKind
+	Pos  cp.Vector
+}
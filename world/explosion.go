@@ -0,0 +1,66 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import "github.com/jakecoffman/cp"
+
+const (
+	rocketBlastRadius  = 1.5 * TileLength
+	rocketBlastImpulse = 800 // at the center of the blast
+	enemyBlastRadius   = 2.5 * TileLength
+	enemyBlastImpulse  = 1200
+	enemyBlastDamage   = 30
+)
+
+// Blasts push and damage everything but sensors
+var filterBlast = cp.NewShapeFilter(cp.NO_GROUP, cp.ALL_CATEGORIES, cp.ALL_CATEGORIES&^categorySensor)
+
+// explode pushes the bodies within the radius of the explosion away from its center, damages the player and the enemies
+// among them, and detonates the rockets it reaches. The impulse and the damage fall off linearly with the distance
+// to the nearest point of each body. The source, like the wreck of an enemy, is left alone if it isn't nil. The body hit
+// directly by a rocket, if it isn't nil, takes the full damage however far its nearest point is.
+func (w *World) explode(pos cp.Vector, radius, damage, impulse float64, source, hit *cp.Body) {
+	// Find the nearest shape of each body first; the space is locked while it is queried
+	nearest := make(map[*cp.Body]cp.PointQueryInfo)
+	var bodies []*cp.Body // in the order they are found, so explosions are deterministic
+	w.space.BBQuery(cp.NewBBForCircle(pos, radius), filterBlast, func(shape *cp.Shape, _ interface{}) {
+		info := shape.PointQuery(pos)
+		if info.Distance > radius {
+			return
+		}
+		body := shape.Body()
+		if body == source {
+			return
+		}
+		if prev, ok := nearest[body]; !ok {
+			bodies = append(bodies, body)
+		} else if prev.Distance <= info.Distance {
+			return
+		}
+		nearest[body] = info
+	}, nil)
+
+	for _, body := range bodies {
+		info := nearest[body]
+		falloff := 1 - cp.Clamp01(info.Distance/radius)
+
+		if rocket, ok := info.Shape.UserData.(*Rocket); ok {
+			rocket.detonate()
+			continue
+		}
+		if body.GetType() == cp.BODY_DYNAMIC {
+			direction := info.Point.Sub(pos)
+			if direction.LengthSq() == 0 {
+				direction = body.Position().Sub(pos)
+			}
+			body.ApplyImpulseAtWorldPoint(direction.Normalize().Mult(impulse*falloff), info.Point)
+		}
+		if body == hit {
+			continue // damaged in full below
+		}
+		w.hitBody(body, damage*falloff)
+	}
+	if hit != nil {
+		w.hitBody(hit, damage)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp"
+)
+
+func TestExplosionImpulseFallsOff(t *testing.T) {
+	w := loadTestWorld(t, testMap(""))
+	w.space.SetGravity(cp.Vector{})
+	const radius, impulse = 64, 800
+	center := cp.Vector{X: 160, Y: 100}
+
+	// Boxes to the right of the explosion, their nearest sides 8, 40 and 72 pixels away
+	var boxes []*cp.Body
+	for _, distance := range []float64{8, 40, 72} {
+		boxes = append(boxes, launchModule(w, center.Add(cp.Vector{X: distance + TileLength/2}), 0))
+	}
+	w.space.Step(DeltaTimeSec) // to index the boxes
+	w.explode(center, radius, 0, impulse, nil, nil)
+
+	for iBox, box := range boxes {
+		distance := box.Position().X - center.X - TileLength/2
+		want := impulse * math.Max(1-distance/radius, 0) / box.Mass()
+		if v := box.Velocity(); math.Abs(v.X-want) > 0.01 || math.Abs(v.Y) > 0.01 {
+			t.Errorf("box %d, %v pixels away, is pushed to %v, not %.2f to the right", iBox, distance, v, want)
+		}
+	}
+}
+
+func TestExplosionsDetonateRockets(t *testing.T) {
+	w := loadTestWorld(t, testMap(""))
+	w.space.SetGravity(cp.Vector{})
+
+	// Rockets in a row, each within the blast radius of the one before but the last
+	var rockets []*Rocket
+	for _, x := range []float64{100, 120, 140, 140 + 2*rocketBlastRadius} {
+		rocket := newRocket(cp.Vector{X: x, Y: 60}, 0, RocketKindRocket, 0, 10, w.space, 1)
+		w.rocketManager.rockets = append(w.rocketManager.rockets, rocket)
+		rockets = append(rockets, rocket)
+	}
+	w.Step(&Input{})
+
+	// One after another
+	rockets[0].detonate()
+	for i := 0; i < 3; i++ {
+		w.Step(&Input{})
+		if n := countEvents(w, EventRocketHit); n != 1 {
+			t.Fatalf("%d rockets exploded in the tick %d of the chain", n, i)
+		}
+	}
+	stepWorld(w, Input{}, ticks(1))
+	if len(w.Rockets()) != 1 || w.Rockets()[0] != rockets[3] {
+		t.Fatalf("%d rockets are left, not the one out of reach", len(w.Rockets()))
+	}
+}
+
+func TestDirectHitDealsFullDamage(t *testing.T) {
+	tmx := withEnemies(testMap(""), `
+  <object id="10" type="enemy" x="240" y="176">
+   <properties>
+    <property name="archetype" value="turret"/>
+   </properties>
+  </object>`)
+	w := loadTestWorld(t, tmx)
+	stepWorld(w, Input{}, ticks(0.5)) // land
+	enemy := w.Enemies[0]
+	health := enemy.Health.Points
+
+	// A rocket hitting the enemy with the player just out of its blast
+	const damage = 40
+	w.rocketManager.rockets = append(w.rocketManager.rockets,
+		newRocket(enemy.Body.Position().Add(cp.Vector{X: -40}), 0, RocketKindRocket, 200, damage, w.space, enemy.group+1))
+	stepUntilEvent(w, EventRocketHit, 1)
+
+	want := health - damage*(1-enemy.Archetype.Armor)
+	if enemy.Health.Points != want {
+		t.Fatalf("enemy hit by a rocket has %.1f points, not %.1f", enemy.Health.Points, want)
+	}
+	if w.Player.Health.Points != playerHealth {
+		t.Fatalf("player out of the blast has %.1f points", w.Player.Health.Points)
+	}
+}
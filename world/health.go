@@ -0,0 +1,48 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+
+	"github.com/lafriks/go-tiled"
+)
+
+// Health is the hit points of a body, taken by the impulses of its collisions and by rocket hits.
+type Health struct {
+	Points     float64
+	MaxPoints  float64
+	threshold  float64 // collision impulses up to it deal no damage
+	perImpulse float64 // damage of each unit of impulse over the threshold
+}
+
+// newHealth returns a full health, reading the max points and the threshold from the properties of the object if it has them.
+func newHealth(obj *tiled.Object, maxPoints, threshold, perImpulse float64) Health {
+	maxPoints = floatProperty(obj, propertyHealth, maxPoints)
+	return Health{
+		Points:     maxPoints,
+		MaxPoints:  maxPoints,
+		threshold:  floatProperty(obj, propertyDamageThreshold, threshold),
+		perImpulse: perImpulse,
+	}
+}
+
+// impactDamage returns the damage of a collision with the impulse.
+func (h *Health) impactDamage(impulse float64) float64 {
+	if impulse <= h.threshold {
+		return 0
+	}
+	return (impulse - h.threshold) * h.perImpulse
+}
+
+func (h *Health) damage(points float64) {
+	h.Points = math.Max(h.Points-points, 0)
+}
+
+// Ratio returns the portion of the max points left.
+func (h *Health) Ratio() float64 {
+	if h.MaxPoints <= 0 {
+		return 0
+	}
+	return h.Points / h.MaxPoints
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+func TestImpactDamage(t *testing.T) {
+	h := Health{Points: 100, MaxPoints: 100, threshold: 50, perImpulse: 0.5}
+	for _, tc := range []struct {
+		impulse, damage float64
+	}{
+		{0, 0},
+		{49, 0},
+		{50, 0},
+		{70, 10},
+		{250, 100},
+	} {
+		if damage := h.impactDamage(tc.impulse); damage != tc.damage {
+			t.Errorf("impulse %v: damage %v, not %v", tc.impulse, damage, tc.damage)
+		}
+	}
+}
+
+func TestHealthOfObject(t *testing.T) {
+	obj := &tiled.Object{Properties: tiled.Properties{
+		{Name: propertyHealth, Type: "float", Value: "40"},
+		{Name: propertyDamageThreshold, Type: "float", Value: "20"},
+	}}
+	h := newHealth(obj, 100, 250, 0.5)
+	if h.Points != 40 || h.MaxPoints != 40 || h.threshold != 20 || h.perImpulse != 0.5 {
+		t.Fatalf("health is %+v", h)
+	}
+	if h := newHealth(&tiled.Object{}, 100, 250, 0.5); h.Points != 100 || h.threshold != 250 {
+		t.Fatalf("health without properties is %+v", h)
+	}
+}
+
+func TestPlayerDamageTakesLives(t *testing.T) {
+	w := loadTestWorld(t, testMap(""))
+	p := w.Player
+	lives := p.NumLives
+
+	p.damage(30)
+	if p.Health.Points != playerHealth-30 || p.NumLives != lives {
+		t.Fatalf("player has %v points and %d lives after 30 damage", p.Health.Points, p.NumLives)
+	}
+	p.damage(playerHealth)
+	if p.NumLives != lives-1 || p.Health.Points != playerHealth {
+		t.Fatalf("player has %v points and %d lives after running out of health", p.Health.Points, p.NumLives)
+	}
+
+	// The last life isn't refilled
+	p.NumLives = 1
+	p.damage(playerHealth)
+	if p.NumLives != 0 || p.Health.Points != 0 {
+		t.Fatalf("player has %v points and %d lives after losing the last life", p.Health.Points, p.NumLives)
+	}
+	p.damage(10)
+	if p.NumLives != 0 {
+		t.Fatalf("player has %d lives after being damaged dead", p.NumLives)
+	}
+}
+
+// launchModule adds a heavy box sliding at the velocity from the position, to run into a body.
+func launchModule(w *World, pos cp.Vector, velocityX float64) *cp.Body {
+	const mass = 10
+	body := w.space.AddBody(cp.NewBody(mass, cp.MomentForBox(mass, TileLength, TileLength)))
+	body.SetPosition(pos)
+	body.SetVelocity(velocityX, 0)
+	w.space.AddShape(cp.NewBox(body, TileLength, TileLength, 0))
+	return body
+}
+
+// moduleImpulse returns the impulse of the module running into a body of the mass at the velocity, with no bounce.
+func moduleImpulse(mass, velocity float64) float64 {
+	const moduleMass = 10
+	return moduleMass * mass / (moduleMass + mass) * velocity
+}
+
+func TestPlayerImpactDamage(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		velocity float64
+	}{
+		{"below the threshold", 0.8 * playerDamageThreshold / moduleImpulse(playerMass, 1)},
+		{"above the threshold", 1.4 * playerDamageThreshold / moduleImpulse(playerMass, 1)},
+	} {
+		w := loadTestWorld(t, testMap(""))
+		stepWorld(w, Input{}, ticks(0.5)) // land
+		launchModule(w, w.Player.Pos.Add(cp.Vector{X: 2 * TileLength}), -tc.velocity)
+
+		stepWorld(w, Input{}, ticks(0.1))
+		want := playerHealth - w.Player.Health.impactDamage(moduleImpulse(playerMass, tc.velocity))
+		if got := w.Player.Health.Points; got < want-5 || got > want+5 {
+			t.Errorf("%s: player has %.1f points, not about %.1f", tc.name, got, want)
+		}
+
+		// Only the first contact deals damage, not the module pushing the player
+		points := w.Player.Health.Points
+		stepWorld(w, Input{}, ticks(0.5))
+		if w.Player.Health.Points != points {
+			t.Errorf("%s: player went from %.1f to %.1f points while being pushed", tc.name, points, w.Player.Health.Points)
+		}
+	}
+}
+
+func TestEnemyImpactDamage(t *testing.T) {
+	robot := enemyArchetypes[defaultEnemyArchetype]
+	for _, tc := range []struct {
+		name    string
+		impulse float64
+		alive   bool
+	}{
+		{"below the threshold", 0.8 * robot.DamageThreshold, true},
+		{"above the threshold", robot.DamageThreshold + 0.5*robot.Health/robot.DamagePerImpulse, true},
+		{"far above the threshold", robot.DamageThreshold + 2*robot.Health/robot.DamagePerImpulse, false},
+	} {
+		tmx := withEnemies(testMap(""), `
+  <object id="10" type="enemy" x="160" y="100"/>`)
+		w := loadTestWorld(t, tmx)
+		// Float the enemy so that it doesn't tip over and hit the floor
+		w.space.SetGravity(cp.Vector{})
+		enemy := w.Enemies[0]
+		launchModule(w, enemy.Body.Position().Add(cp.Vector{X: 2 * TileLength}), -tc.impulse/moduleImpulse(robot.Mass, 1))
+
+		stepWorld(w, Input{}, ticks(0.1))
+		if enemy.IsAlive != tc.alive {
+			t.Errorf("%s: enemy alive is %v", tc.name, enemy.IsAlive)
+			continue
+		}
+		want := math.Max(robot.Health-enemy.Health.impactDamage(tc.impulse), 0)
+		if got := enemy.Health.Points; got < want-5 || got > want+5 {
+			t.Errorf("%s: enemy has %.1f points, not about %.1f", tc.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import "github.com/jakecoffman/cp"
+
+type GunInput uint8
+
+const (
+	GunInputNone GunInput = iota
+	GunInputAttract
+	GunInputRepel
+)
+
+// Input holds the controls of the player for a tick.
+type Input struct {
+	Aim      cp.Vector // point in the world the gun is aimed at
+	Up       bool
+	Left     bool
+	Right    bool
+	Gun      GunInput
+	Activate bool
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+// magnet is how the gun acts on a shape.
+type magnet struct {
+	metal    bool    // the gun only acts on metal
+	strength float64 // multiplier of the gun force
+	movable  bool    // whether the shape is pulled and pushed too, or only the player is
+}
+
+// newMagnet reads the magnetic properties of the object. Objects are metal of strength 1 by default.
+func newMagnet(obj *tiled.Object, movable bool) magnet {
+	return objectMagnet(obj, magnet{metal: true, strength: 1, movable: movable})
+}
+
+// objectMagnet returns the magnetic properties of the object, or the defaults for the ones it doesn't set.
+func objectMagnet(obj *tiled.Object, def magnet) magnet {
+	return magnet{
+		metal:    boolProperty(obj, propertyMetal, def.metal),
+		strength: floatProperty(obj, propertyMagnetStrength, def.strength),
+		movable:  boolProperty(obj, propertyMovable, def.movable),
+	}
+}
+
+// addMagnet sets the magnetic properties of the shape.
+func (w *World) addMagnet(shape *cp.Shape, m magnet) {
+	if w.magnets == nil {
+		w.magnets = make(map[*cp.Shape]magnet)
+	}
+	w.magnets[shape] = m
+}
+
+// magnetOf returns the magnetic properties of the shape. Shapes without any, like rockets, are metal and movable
+// if they have dynamic bodies.
+func (w *World) magnetOf(shape *cp.Shape) magnet {
+	if m, ok := w.magnets[shape]; ok {
+		return m
+	}
+	return magnet{
+		metal:    true,
+		strength: 1,
+		movable:  shape.Body().GetType() == cp.BODY_DYNAMIC,
+	}
+}
+
+// applyGunForce sends the negative of the player's gun force to the shape hit by the ray, at the hit point.
+func (w *World) applyGunForce(m magnet) {
+	body := w.RayHitInfo.Shape.Body()
+	if !m.movable || body.GetType() != cp.BODY_DYNAMIC {
+		return
+	}
+	body.ApplyForceAtWorldPoint(w.Player.gunForce.Neg(), w.RayHitInfo.Point)
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp"
+)
+
+// magnetWall returns an electric wall in front of the player with the properties.
+func magnetWall(properties string) string {
+	return `
+  <object id="10" type="electricWall" x="150" y="100" width="8" height="100">
+   <properties>` + properties + `
+   </properties>
+  </object>`
+}
+
+func TestMagnetPullsPlayer(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		properties string
+		forceMult  float64
+	}{
+		{"metal by default", "", 1},
+		{"not metal", `
+    <property name="metal" type="bool" value="false"/>`, 0},
+		{"strong", `
+    <property name="magnetStrength" type="float" value="2"/>`, 2},
+	} {
+		w := loadTestWorld(t, testMap(magnetWall(tc.properties)))
+		stepWorld(w, Input{}, ticks(0.5)) // land
+		aim := cp.Vector{X: 150, Y: w.Player.Pos.Y}
+		stepWorld(w, Input{Aim: aim}, 1)
+		stepWorld(w, Input{Aim: aim, Gun: GunInputAttract}, 1)
+
+		info := w.RayHitInfo
+		want := math.Min(gunForceMult/(info.Alpha*info.Alpha), gunForceMax) * tc.forceMult
+		if got := w.Player.gunForce.Length(); math.Abs(got-want) > 1e-6 {
+			t.Errorf("%s: gun force is %.1f, not %.1f", tc.name, got, want)
+		}
+	}
+}
+
+func TestMagnetMovesMovableBodies(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		properties string
+		moves      bool
+	}{
+		{"enemy", "", true},
+		{"fixed enemy", `
+   <properties>
+    <property name="movable" type="bool" value="false"/>
+   </properties>`, false},
+	} {
+		tmx := withEnemies(testMap(""), `
+  <object id="10" type="enemy" x="160" y="120">`+tc.properties+`
+  </object>`)
+		w := loadTestWorld(t, tmx)
+		w.space.SetGravity(cp.Vector{}) // float the enemy
+		enemy := w.Enemies[0]
+		posEnemy := enemy.Body.Position()
+
+		stepWorld(w, Input{Aim: posEnemy, Gun: GunInputRepel}, ticks(0.5))
+		if moved := enemy.Body.Position().Distance(posEnemy) > 1; moved != tc.moves {
+			t.Errorf("%s: enemy moved is %v, from %v to %v", tc.name, moved, posEnemy, enemy.Body.Position())
+		}
+	}
+}
+
+func TestMagnetTurnsBodies(t *testing.T) {
+	w := loadTestWorld(t, testMap(""))
+	w.space.SetGravity(cp.Vector{})
+	stepWorld(w, Input{}, 1)
+	box := launchModule(w, cp.Vector{X: 200, Y: w.Player.PosGun.Y}, 0)
+
+	// Pull the box by its upper half
+	stepWorld(w, Input{Aim: box.Position().Add(cp.Vector{Y: -TileLength / 4}), Gun: GunInputAttract}, ticks(0.2))
+	if box.AngularVelocity() == 0 {
+		t.Fatal("box pulled at its upper half doesn't turn")
+	}
+}
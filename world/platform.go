@@ -0,0 +1,113 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+const platformSpeed = 48.0 // pixels per second
+
+func init() {
+	registerEntity(ObjectTypePlatform, newPlatform)
+}
+
+// Platform is a kinematic body following the path of a polyline object. It goes back and forth along the path,
+// or from its last point to the first one if it loops. Bodies on it are carried by the friction of its surface.
+type Platform struct {
+	entityBase
+	Body      *cp.Body
+	Start     cp.Vector // center of the platform's object, where the platform starts
+	Width     float64
+	Height    float64
+	Moving    bool
+	shape     *cp.Shape
+	path      []cp.Vector
+	iNext     int // index of the path point the platform is heading to
+	direction int // along the path, 1 or -1
+	loop      bool
+	speed     float64
+	triggered bool // started moving by a trigger
+}
+
+func newPlatform(obj *tiled.Object, w *World) Entity {
+	p := &Platform{
+		entityBase: newEntityBase(obj),
+		Width:      obj.Width,
+		Height:     obj.Height,
+		Moving:     boolProperty(obj, propertyMoving, true),
+		direction:  1,
+		loop:       obj.Properties.GetBool(propertyLoop),
+		speed:      floatProperty(obj, propertySpeed, platformSpeed),
+	}
+	p.Start = p.pos
+	// Move the path so that it starts at the start
+	path := w.objectPath(obj)
+	for _, point := range path {
+		p.path = append(p.path, p.Start.Add(point.Sub(path[0])))
+	}
+	if len(p.path) > 1 {
+		p.iNext = 1
+	}
+
+	p.Body = w.space.AddBody(cp.NewKinematicBody())
+	p.Body.SetPosition(p.Start)
+
+	p.shape = w.space.AddShape(cp.NewBox(p.Body, obj.Width, obj.Height, 0))
+	p.shape.SetElasticity(wallElasticity)
+	p.shape.SetFriction(wallFriction)
+	p.shape.SetCollisionType(collisionTypeWall)
+	p.shape.SetFilter(filterWall)
+	w.addMagnet(p.shape, newMagnet(obj, false))
+
+	return p
+}
+
+func (p *Platform) Position() cp.Vector {
+	return p.Body.Position()
+}
+
+// update sets the velocity of the platform for the next step.
+func (p *Platform) update(w *World) {
+	if !p.Moving || len(p.path) < 2 {
+		p.Body.SetVelocityVector(cp.Vector{})
+		return
+	}
+
+	toNext := p.path[p.iNext].Sub(p.Body.Position())
+	if toNext.Length() > p.speed*DeltaTimeSec {
+		p.Body.SetVelocityVector(toNext.Normalize().Mult(p.speed))
+		return
+	}
+
+	// Reach the point in the next step and head to the one after it
+	p.Body.SetVelocityVector(toNext.Mult(1.0 / DeltaTimeSec))
+	iNext := p.iNext + p.direction
+	if iNext < 0 || iNext >= len(p.path) {
+		if p.loop {
+			iNext = (iNext + len(p.path)) % len(p.path)
+		} else {
+			p.direction = -p.direction
+			iNext = p.iNext + p.direction
+		}
+	}
+	p.iNext = iNext
+}
+
+// trigger starts the platform.
+func (p *Platform) trigger(w *World) {
+	if p.Moving {
+		return
+	}
+	p.Moving = true
+	p.triggered = true
+}
+
+func (p *Platform) saved() bool {
+	return p.triggered
+}
+
+func (p *Platform) restore(w *World) {
+	p.trigger(w)
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp"
+)
+
+// platformObjects returns a platform following a path 64 pixels to the right and 32 down, with the properties.
+func platformObjects(properties string) string {
+	return `
+  <object id="10" type="platform" x="100" y="100" width="48" height="8">
+   <properties>
+    <property name="path" type="object" value="11"/>
+` + properties + `
+   </properties>
+  </object>
+  <object id="11" x="0" y="0"><polyline points="0,0 64,0 64,32"/></object>
+`
+}
+
+// closeTo reports whether the vectors are within a pixel.
+func closeTo(a, b cp.Vector) bool {
+	return a.Distance(b) < 1
+}
+
+func TestPlatformFollowsPath(t *testing.T) {
+	w := loadTestWorld(t, testMap(platformObjects("")))
+	platform := w.entityByID[10].(*Platform)
+	start := platform.Start
+	corner, end := start.Add(cp.Vector{X: 64}), start.Add(cp.Vector{X: 64, Y: 32})
+
+	stepWorld(w, Input{}, ticks(64/platformSpeed))
+	if !closeTo(platform.Position(), corner) {
+		t.Fatalf("platform is at %v, not at the corner of the path %v", platform.Position(), corner)
+	}
+	stepWorld(w, Input{}, ticks(32/platformSpeed))
+	if !closeTo(platform.Position(), end) {
+		t.Fatalf("platform is at %v, not at the end of the path %v", platform.Position(), end)
+	}
+
+	// Back and forth
+	stepWorld(w, Input{}, ticks(96/platformSpeed))
+	if !closeTo(platform.Position(), start) {
+		t.Fatalf("platform is at %v, not back at the start %v", platform.Position(), start)
+	}
+}
+
+func TestLoopingPlatform(t *testing.T) {
+	w := loadTestWorld(t, testMap(platformObjects(`<property name="loop" type="bool" value="true"/>`)))
+	platform := w.entityByID[10].(*Platform)
+	start := platform.Start
+
+	// From the end straight to the start, along the diagonal
+	diagonal := math.Hypot(64, 32)
+	stepWorld(w, Input{}, ticks(96/platformSpeed)+ticks(diagonal/platformSpeed))
+	if !closeTo(platform.Position(), start) {
+		t.Fatalf("platform is at %v, not back at the start %v", platform.Position(), start)
+	}
+	stepWorld(w, Input{}, ticks(0.5))
+	if pos := platform.Position(); pos.Y != start.Y || pos.X <= start.X {
+		t.Fatalf("platform at %v isn't heading right again from the start %v", pos, start)
+	}
+}
+
+func TestTriggeredPlatform(t *testing.T) {
+	tmx := testMap(platformObjects(`<property name="moving" type="bool" value="false"/>`))
+	w := loadTestWorld(t, tmx)
+	platform := w.entityByID[10].(*Platform)
+
+	stepWorld(w, Input{}, ticks(1))
+	if platform.Position() != platform.Start {
+		t.Fatalf("platform moved to %v before it is triggered", platform.Position())
+	}
+
+	w.triggerTargets([]uint32{10})
+	stepWorld(w, Input{}, ticks(1))
+	if platform.Position().X <= platform.Start.X {
+		t.Fatalf("platform is at %v after it is triggered", platform.Position())
+	}
+
+	// It is moving again after respawning
+	restored := loadTestWorld(t, tmx)
+	restored.Restore(w.Progress())
+	if !restored.entityByID[10].(*Platform).Moving {
+		t.Fatal("triggered platform isn't moving after restoring")
+	}
+}
+
+func TestPlatformCarriesPlayer(t *testing.T) {
+	w := loadTestWorld(t, testMap(platformObjects("")))
+	platform := w.entityByID[10].(*Platform)
+
+	w.Player.Body.SetPosition(platform.Start.Add(cp.Vector{Y: -16}))
+	stepWorld(w, Input{}, ticks(1))
+	if dx := w.Player.Pos.X - platform.Position().X; math.Abs(dx) > 8 {
+		t.Fatalf("player at %v is left behind by the platform at %v", w.Player.Pos, platform.Position())
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+
+	"github.com/jakecoffman/cp"
+)
+
+type PlayerState uint8
+
+const (
+	StateIdle PlayerState = iota
+	StateWalking
+	StateFiring
+	StateJumping
+	StateTotal
+)
+
+const (
+	halfPi        = math.Pi / 2.0
+	turnTolerance = 5 * cp.RadianConst
+)
+
+const (
+	playerWidthTile  = 10.0 / 16.0
+	playerHeightTile = 1.6
+)
+
+const (
+	gravity          = 750.0
+	playerMass       = 0.75
+	playerElasticity = 0.0
+	// Taken from cp-examples/player and modified
+	playerFriction        = playerGroundAccel / (4 * gravity)
+	playerVelocity        = 150.0
+	playerGroundAccelTime = 0.05
+	playerGroundAccel     = playerVelocity / playerGroundAccelTime
+	jumpHeightTile        = 1.5
+	//
+)
+
+const (
+	gunRange     = MapWidth
+	gunForceMult = 15
+	gunForceMax  = 750
+	gunMinAlpha  = 1e-5 // required to prevent player pos to go NaN
+)
+
+const (
+	playerStartLives       = 4
+	playerHealth           = 100 // of each life
+	playerDamageThreshold  = 250 // collision impulse, about the landing of a fall of five tiles
+	playerDamagePerImpulse = 0.5
+)
+
+type GunState uint8
+
+const (
+	GunStateIdle GunState = iota
+	GunStateAttract
+	GunStateRepel
+)
+
+var posGunRelative = cp.Vector{X: TileLength / 7.0, Y: -TileLength / 4.0}
+
+type Player struct {
+	Pos        cp.Vector
+	PosGun     cp.Vector
+	AngleGun   float64
+	Body       *cp.Body
+	State      PlayerState
+	StateGun   GunState
+	NumLives   int
+	Health     Health // of the current life
+	TurnedLeft bool
+	shape      *cp.Shape
+	onGround   bool
+	gunRay     [2]cp.Vector
+	gunForce   cp.Vector
+}
+
+func newPlayer(pos cp.Vector, space *cp.Space, health Health) *Player {
+	player := &Player{
+		Pos:      pos,
+		State:    StateIdle,
+		NumLives: playerStartLives,
+		Health:   health,
+	}
+
+	player.Body = cp.NewBody(playerMass, cp.INFINITY)
+	player.Body.SetPosition(cp.Vector{X: pos.X, Y: pos.Y})
+	player.Body.SetVelocityUpdateFunc(playerUpdateVelocity)
+	player.shape = cp.NewBox(player.Body, playerWidthTile*TileLength, playerHeightTile*TileLength, 0)
+	player.shape.SetElasticity(playerElasticity)
+	player.shape.SetCollisionType(collisionTypePlayer)
+	player.shape.SetFilter(filterPlayer)
+
+	space.AddBody(player.Body)
+	space.AddShape(player.shape)
+
+	return player
+}
+
+func (p *Player) update(inp *Input, rayHitInfo *cp.SegmentQueryInfo, rayHitMagnet magnet) {
+	// Update position
+	p.Pos = p.Body.Position()
+	// if p.NumLives <= 0 {
+	// 	p.Body.SetMoment(100)
+	// }
+
+	// Update gun position and angle
+	gunPosLeft := p.Pos.Add(cp.Vector{X: -posGunRelative.X, Y: posGunRelative.Y})
+	gunPosRight := p.Pos.Add(posGunRelative)
+
+	distX := inp.Aim.X - gunPosLeft.X
+	distY := inp.Aim.Y - gunPosLeft.Y
+	angleGunLeft := math.Atan2(distY, distX)
+
+	distX = inp.Aim.X - gunPosRight.X
+	distY = inp.Aim.Y - gunPosRight.Y
+	angleGunRight := math.Atan2(distY, distX)
+
+	leftSaysTurnLeft := (angleGunLeft < -halfPi) || (angleGunLeft > halfPi)
+	rightSaysTurnLeft := (angleGunRight < -halfPi) || (angleGunRight > halfPi)
+
+	if leftSaysTurnLeft && rightSaysTurnLeft {
+		p.PosGun = gunPosLeft
+		p.AngleGun = angleGunLeft
+		p.TurnedLeft = true
+	} else if !leftSaysTurnLeft && !rightSaysTurnLeft {
+		p.PosGun = gunPosRight
+		p.AngleGun = angleGunRight
+		p.TurnedLeft = false
+	} else if p.TurnedLeft {
+		p.PosGun = gunPosLeft
+		p.AngleGun = angleGunLeft
+	} else {
+		p.PosGun = gunPosRight
+		p.AngleGun = angleGunRight
+	}
+
+	p.checkOnGround()
+
+	// Raycast
+	const rayLength = gunRange
+	p.gunRay[0] = p.PosGun
+	p.gunRay[1] = p.gunRay[0].Add(cp.Vector{
+		X: rayLength * math.Cos(p.AngleGun), Y: rayLength * math.Sin(p.AngleGun),
+	})
+
+	p.handleInputs(inp, rayHitInfo, rayHitMagnet)
+
+	// v := p.Body.Velocity()
+	// fmt.Printf("Friction: %.2f\tVel X: %.2f\tVel Y: %.2f\n", p.shape.Friction(), v.X, v.Y)
+}
+
+func (p *Player) checkOnGround() {
+	const groundNormalYThreshold = 0.8
+	// Grab the grounding normal from last frame - Taken from cp-examples/player and modified
+	groundNormal := cp.Vector{}
+	p.Body.EachArbiter(func(arb *cp.Arbiter) {
+		n := arb.Normal() //.Neg()
+
+		if n.Y > groundNormal.Y {
+			groundNormal = n
+		}
+	})
+	p.onGround = groundNormal.Y > groundNormalYThreshold
+}
+
+func (p *Player) handleInputs(input *Input, rayHitInfo *cp.SegmentQueryInfo, rayHitMagnet magnet) {
+	p.State = StateIdle
+
+	// Handle inputs
+	var surfaceV cp.Vector
+	if input.Right {
+		surfaceV.X = -playerVelocity
+		p.State = StateWalking
+	} else if input.Left {
+		surfaceV.X = playerVelocity
+		p.State = StateWalking
+	}
+	p.shape.SetSurfaceV(surfaceV)
+	if p.onGround {
+		p.shape.SetFriction(playerFriction)
+	} else {
+		p.shape.SetFriction(0)
+	}
+
+	if input.Up && p.onGround {
+		jumpV := math.Sqrt(2.0 * jumpHeightTile * TileLength * gravity) // Taken from cp-examples/player
+		p.Body.SetVelocityVector(p.Body.Velocity().Add(cp.Vector{X: 0, Y: -jumpV}))
+	}
+	// Apply air control if not on ground
+	if !p.onGround {
+		v := p.Body.Velocity()
+		newVelX := cp.Clamp(v.X-surfaceV.X*DeltaTimeSec, -playerVelocity, playerVelocity)
+		p.Body.SetVelocity(newVelX, v.Y)
+		p.State = StateJumping
+	}
+
+	// Apply magnetic force if fire is pressed
+	p.gunForce = cp.Vector{}
+	if (input.Gun != GunInputNone) && rayHitInfo.Alpha >= gunMinAlpha {
+		forceDirection := rayHitInfo.Point.Sub(p.Pos).Normalize()
+		p.gunForce = forceDirection.Mult(gunForceMult).Mult(1 / (rayHitInfo.Alpha * rayHitInfo.Alpha))
+		p.gunForce = p.gunForce.Clamp(gunForceMax).Mult(rayHitMagnet.strength)
+		if !rayHitMagnet.metal {
+			p.gunForce = cp.Vector{}
+		}
+
+		p.StateGun = GunStateAttract
+		if input.Gun == GunInputRepel {
+			p.gunForce = p.gunForce.Neg()
+			p.StateGun = GunStateRepel
+		}
+		p.Body.SetForce(p.gunForce)
+		p.State = StateFiring
+	} else {
+		p.StateGun = GunStateIdle
+	}
+	// v := p.Body.Velocity()
+	// fmt.Printf("Velocity X: %.2f\tY: %.2f\t\tForce X: %.2f\tY:%.2f\n", v.X, v.Y, p.gunForce.X, p.gunForce.Y)
+	// fmt.Printf("Force X:%.2f\tY:%.2f\n", p.gunForce.X, p.gunForce.Y)
+}
+
+// damage takes the points from the health of the player. A life is lost when the health runs out.
+func (p *Player) damage(points float64) {
+	if p.NumLives <= 0 {
+		return
+	}
+	p.Health.damage(points)
+	if p.Health.Points > 0 {
+		return
+	}
+
+	p.NumLives--
+	if p.NumLives > 0 {
+		p.Health.Points = p.Health.MaxPoints
+	}
+}
+
+// impact is called by the collision handler with the impulse of the first contact of a collision.
+func (p *Player) impact(impulse float64) {
+	p.damage(p.Health.impactDamage(impulse))
+}
+
+func playerUpdateVelocity(body *cp.Body, gravity cp.Vector, damping, dt float64) {
+	body.UpdateVelocity(gravity, damping, dt)
+}
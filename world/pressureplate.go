@@ -0,0 +1,68 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+const (
+	plateMinMass     = 1.0 // more than the player alone
+	plateSenseHeight = 4.0 // above the plate
+)
+
+func init() {
+	registerEntity(ObjectTypePressurePlate, newPressurePlate)
+}
+
+// PressurePlate holds its targets while bodies heavy enough rest on it, and releases them when they leave.
+// Targets that can't be held, like terminals, are triggered instead.
+type PressurePlate struct {
+	entityBase
+	Width   float64
+	Height  float64
+	Pressed bool
+	minMass float64
+	shape   *cp.Shape // not in the space, only used to query it
+	targets []uint32
+	bodies  map[*cp.Body]bool // on the plate, reused every tick
+}
+
+func newPressurePlate(obj *tiled.Object, w *World) Entity {
+	bb := cp.BB{L: obj.X, T: obj.Y + obj.Height, R: obj.X + obj.Width, B: obj.Y - plateSenseHeight}
+	shape := cp.NewBox2(w.space.StaticBody, bb, 0)
+	shape.SetSensor(true)
+	shape.SetFilter(filterPlate)
+
+	return &PressurePlate{
+		entityBase: newEntityBase(obj),
+		Width:      obj.Width,
+		Height:     obj.Height,
+		minMass:    floatProperty(obj, propertyMass, plateMinMass),
+		shape:      shape,
+		targets:    objectTargets(obj),
+		bodies:     make(map[*cp.Body]bool),
+	}
+}
+
+// update presses or releases the plate by the mass of the bodies on it.
+func (p *PressurePlate) update(w *World) {
+	for body := range p.bodies {
+		delete(p.bodies, body)
+	}
+	var mass float64
+	w.space.ShapeQuery(p.shape, func(shape *cp.Shape, _ *cp.ContactPointSet) {
+		if body := shape.Body(); !p.bodies[body] {
+			p.bodies[body] = true
+			mass += body.Mass()
+		}
+	})
+
+	pressed := mass >= p.minMass
+	if pressed == p.Pressed {
+		return
+	}
+	p.Pressed = pressed
+	w.holdTargets(p.targets, pressed)
+}
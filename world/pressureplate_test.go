@@ -0,0 +1,83 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"testing"
+
+	"github.com/jakecoffman/cp"
+)
+
+const plateObjects = `
+  <object id="10" type="pressurePlate" x="96" y="196" width="32" height="4">
+   <properties>
+    <property name="target" type="object" value="12"/>
+   </properties>
+  </object>
+  <object id="11" type="crate" x="104" y="150" width="16" height="16"/>
+  <object id="12" type="electricWall" x="200" y="100" width="8" height="100"/>
+`
+
+func TestPlateHoldsWallUnderCrate(t *testing.T) {
+	w := loadTestWorld(t, testMap(plateObjects))
+	plate := w.entityByID[10].(*PressurePlate)
+	crate := w.entityByID[11].(*Crate)
+	wall := w.entityByID[12].(*ElectricWall)
+
+	// The crate falls on the plate
+	stepWorld(w, Input{}, ticks(1))
+	if !plate.Pressed || !wall.Held {
+		t.Fatalf("plate under the crate at %v isn't holding the wall", crate.Position())
+	}
+	if w.space.ContainsShape(wall.shapes[0]) {
+		t.Fatal("held wall is still in the space")
+	}
+
+	// Take the crate away
+	crate.Body.SetPosition(cp.Vector{X: 260, Y: 150})
+	stepWorld(w, Input{}, ticks(0.5))
+	if plate.Pressed || wall.Held {
+		t.Fatal("plate is still holding the wall after the crate left")
+	}
+	if !w.space.ContainsShape(wall.shapes[0]) {
+		t.Fatal("released wall isn't back in the space")
+	}
+}
+
+func TestPlateIgnoresLightBodies(t *testing.T) {
+	w := loadTestWorld(t, testMap(plateObjects))
+	plate := w.entityByID[10].(*PressurePlate)
+
+	// Only the player stands on the plate
+	w.entityByID[11].(*Crate).Body.SetPosition(cp.Vector{X: 260, Y: 150})
+	w.Player.Body.SetPosition(plate.Position().Add(cp.Vector{Y: -20}))
+	stepWorld(w, Input{}, ticks(1))
+	if plate.Pressed {
+		t.Fatalf("plate is pressed by the player alone, at %v", w.Player.Pos)
+	}
+}
+
+func TestTriggeredWallStaysRemovedAfterRelease(t *testing.T) {
+	w := loadTestWorld(t, testMap(plateObjects+`
+  <object id="13" type="terminal" x="40" y="184" width="16" height="16">
+   <properties>
+    <property name="target" type="object" value="12"/>
+   </properties>
+  </object>`))
+	crate := w.entityByID[11].(*Crate)
+	wall := w.entityByID[12].(*ElectricWall)
+
+	// The plate holds the wall, then the terminal triggers it too
+	stepWorld(w, Input{}, ticks(1))
+	w.triggerTargets([]uint32{13})
+	stepWorld(w, Input{}, ticks(0.5))
+	if !wall.Held || !wall.Removed {
+		t.Fatalf("wall held %v, removed %v", wall.Held, wall.Removed)
+	}
+
+	crate.Body.SetPosition(cp.Vector{X: 260, Y: 150})
+	stepWorld(w, Input{}, ticks(0.5))
+	if wall.Held || w.space.ContainsShape(wall.shapes[0]) {
+		t.Fatal("triggered wall is back after the plate released it")
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+
+	"github.com/jakecoffman/cp"
+)
+
+const (
+	rocketMass         = 0.25
+	rocketMoment       = 10
+	rocketWidth        = 8
+	rocketHeight       = 2
+	grenadeLaunchAngle = 0.4 // radians above the line of fire
+)
+
+// LaserShowSec is how long a laser is shown after it is fired.
+const LaserShowSec = 0.15
+
+// RocketKind is the kind of projectile a weapon fires.
+type RocketKind uint8
+
+const (
+	RocketKindRocket  RocketKind = iota // flies straight
+	RocketKindHoming                    // steers towards the player
+	RocketKindGrenade                   // falls and bounces off walls until its fuse burns out
+	RocketKindPlasma                    // slow orb the gun can't deflect, fading out after a while
+	RocketKindLaser                     // hits instantly, fired as a laser instead of a rocket
+)
+
+// Names of the rocket kinds in the enemies data file
+var rocketKindNames = map[string]RocketKind{
+	"rocket":  RocketKindRocket,
+	"homing":  RocketKindHoming,
+	"grenade": RocketKindGrenade,
+	"plasma":  RocketKindPlasma,
+	"laser":   RocketKindLaser,
+}
+
+// rocketKind is how a kind of rocket flies and how the gun acts on it.
+type rocketKind struct {
+	mass          float64
+	width, height float64
+	round         bool    // a circle as wide as the rocket
+	gravityScale  float64 // portion of the gravity pulling the rocket
+	elasticity    float64
+	turnRate      float64 // radians per second, of homing rockets
+	fuseSec       float64 // rockets with a fuse bounce off walls until it burns out
+	lifeSec       float64 // rockets fade out after it without exploding, if set
+	magnet        magnet
+}
+
+var rocketKinds = [...]rocketKind{
+	RocketKindRocket: {
+		mass: rocketMass, width: rocketWidth, height: rocketHeight,
+		magnet: magnet{metal: true, strength: 1, movable: true},
+	},
+	RocketKindHoming: {
+		mass: rocketMass, width: rocketWidth, height: rocketHeight, turnRate: 1.5,
+		magnet: magnet{metal: true, strength: 2, movable: true},
+	},
+	RocketKindGrenade: {
+		mass: 0.4, width: 5, height: 5, round: true, gravityScale: 1, elasticity: 0.6, fuseSec: 2.5,
+		magnet: magnet{metal: true, strength: 1.5, movable: true},
+	},
+	RocketKindPlasma: {
+		mass: 0.1, width: 6, height: 6, round: true, lifeSec: 8,
+		magnet: magnet{metal: false},
+	},
+}
+
+type Rocket struct {
+	Body    *cp.Body
+	Kind    RocketKind
+	shape   *cp.Shape
+	damage  float64
+	ageSec  float64
+	hitBody *cp.Body // first body the rocket collided with, set by the collision handler
+	blown   bool     // detonated by another explosion
+}
+
+// Laser is the ray of a laser fired, shown for a moment.
+type Laser struct {
+	Start, End cp.Vector
+	ShowSec    float64 // left
+}
+
+func newRocket(startPos cp.Vector, angle float64, kind RocketKind, velocity, damage float64, space *cp.Space, group uint) *Rocket {
+	k := rocketKinds[kind]
+	body := cp.NewBody(k.mass, rocketMoment)
+	body.SetPosition(startPos)
+	body.SetVelocityUpdateFunc(rocketUpdateVelocity)
+	body.SetAngle(angle)
+	body.SetVelocity(velocity*math.Cos(angle), velocity*math.Sin(angle))
+
+	var shape *cp.Shape
+	if k.round {
+		shape = cp.NewCircle(body, k.width/2.0, cp.Vector{})
+	} else {
+		shape = cp.NewBox(body, k.width, k.height, 0)
+	}
+	shape.SetElasticity(k.elasticity)
+	shape.SetCollisionType(collisionTypeRocket)
+	shape.SetFilter(filterRocket(group))
+
+	space.AddBody(body)
+	space.AddShape(shape)
+
+	rocket := &Rocket{Body: body, Kind: kind, shape: shape, damage: damage}
+	shape.UserData = rocket
+	return rocket
+}
+
+// Width returns the width of the rocket, or the diameter of a round one.
+func (r *Rocket) Width() float64 {
+	return rocketKinds[r.Kind].width
+}
+
+// bounces reports whether the rocket bounces off the shape instead of exploding.
+func (r *Rocket) bounces(shape *cp.Shape) bool {
+	return rocketKinds[r.Kind].fuseSec > 0 && shape.Filter.Categories&(categoryPlayer|categoryEnemy) == 0
+}
+
+// detonate makes the rocket explode in the next update, like when another explosion reaches it.
+// Chains of rockets blow up one after another this way.
+func (r *Rocket) detonate() {
+	r.blown = true
+}
+
+// steer turns the velocity of the rocket towards the target, by the turn rate at most.
+func (r *Rocket) steer(target cp.Vector, turnRate float64) {
+	velocity := r.Body.Velocity()
+	angle := velocity.ToAngle()
+	diff := math.Remainder(target.Sub(r.Body.Position()).ToAngle()-angle, 2*math.Pi)
+	angle += cp.Clamp(diff, -turnRate*DeltaTimeSec, turnRate*DeltaTimeSec)
+	r.Body.SetVelocityVector(cp.ForAngle(angle).Mult(velocity.Length()))
+	r.Body.SetAngle(angle)
+}
+
+type rocketManager struct {
+	rockets []*Rocket
+	lasers  []*Laser
+	space   *cp.Space
+}
+
+// update returns the rockets that exploded, hitting a body, by their fuse or detonated by other explosions.
+func (m *rocketManager) update(w *World) (explodedRockets []*Rocket) {
+	rockets := m.rockets[:0] // the ones still flying
+	for _, rocket := range m.rockets {
+		k := rocketKinds[rocket.Kind]
+		rocket.ageSec += DeltaTimeSec
+		if rocket.hitBody != nil || rocket.blown || (k.fuseSec > 0 && rocket.ageSec >= k.fuseSec) {
+			w.emit(EventRocketHit, rocket.Body.Position())
+			explodedRockets = append(explodedRockets, rocket)
+			m.remove(w, rocket)
+			continue
+		}
+		if k.lifeSec > 0 && rocket.ageSec >= k.lifeSec {
+			m.remove(w, rocket)
+			continue
+		}
+		if k.turnRate > 0 && w.Player.NumLives > 0 {
+			rocket.steer(w.Player.Pos, k.turnRate)
+		}
+
+		// Eliminate the gravity the rocket isn't pulled by
+		// velocityPercent := rocket.Body.Velocity().Length() / velocity // To eliminate floating stopped rockets
+		rocket.Body.SetForce(cp.Vector{X: 0, Y: -gravity * k.mass * (1 - k.gravityScale) /* * velocityPercent*/})
+
+		rockets = append(rockets, rocket)
+	}
+
+	// TODO: Object pooling?
+	// Let the deleted rockets be collected
+	for iRocket := len(rockets); iRocket < len(m.rockets); iRocket++ {
+		m.rockets[iRocket] = nil
+	}
+	m.rockets = rockets
+
+	// Fade out lasers
+	lasers := m.lasers[:0]
+	for _, laser := range m.lasers {
+		laser.ShowSec -= DeltaTimeSec
+		if laser.ShowSec > 0 {
+			lasers = append(lasers, laser)
+		}
+	}
+	m.lasers = lasers
+
+	return
+}
+
+// remove deletes the rocket from the space.
+func (m *rocketManager) remove(w *World, rocket *Rocket) {
+	m.space.RemoveShape(rocket.shape)
+	m.space.RemoveBody(rocket.Body)
+	delete(w.magnets, rocket.shape)
+}
+
+// fireLaser hits the first body in the direction of the angle within the range instantly.
+// Lasers pass through the enemy firing them, like its rockets do.
+func (w *World) fireLaser(start cp.Vector, angle, distance, damage float64, group uint) {
+	end := start.Add(cp.ForAngle(angle).Mult(distance))
+	info := w.space.SegmentQueryFirst(start, end, 0, filterRocket(group))
+	if info.Shape != nil {
+		end = info.Point
+		w.emit(EventRocketHit, end)
+		w.hitBody(info.Shape.Body(), damage)
+	}
+	w.rocketManager.lasers = append(w.rocketManager.lasers, &Laser{Start: start, End: end, ShowSec: LaserShowSec})
+}
+
+func rocketUpdateVelocity(body *cp.Body, gravity cp.Vector, damping, dt float64) {
+	body.UpdateVelocity(gravity, damping, dt)
+}
@@ -0,0 +1,175 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp"
+)
+
+func TestHomingRocketSteers(t *testing.T) {
+	w := loadTestWorld(t, testMap(""))
+	stepWorld(w, Input{}, ticks(0.5)) // land
+	rocket := fireRocket(w, cp.Vector{X: 200, Y: 60}, 0, RocketKindHoming, 1)
+	stepWorld(w, Input{}, 1)
+
+	// Fired away from the player, it turns towards the player by its turn rate
+	const sec = 0.5
+	angle := rocket.Body.Velocity().ToAngle()
+	stepWorld(w, Input{}, ticks(sec))
+	turned := rocket.Body.Velocity().ToAngle() - angle
+	if want := rocketKinds[RocketKindHoming].turnRate * sec; math.Abs(turned-want) > 0.05 {
+		t.Fatalf("homing rocket turned %.2f radians in %v seconds, not %.2f", turned, sec, want)
+	}
+	if rocket.Body.Velocity().Y <= 0 {
+		t.Fatalf("homing rocket with velocity %v isn't turning down to the player", rocket.Body.Velocity())
+	}
+}
+
+func TestGrenadeBlast(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		dx     float64 // from the player
+		damage bool
+	}{
+		{"within the blast radius", rocketBlastRadius - 4, true},
+		{"out of the blast radius", rocketBlastRadius + 16, false},
+	} {
+		w := loadTestWorld(t, testMap(""))
+		stepWorld(w, Input{}, ticks(0.5)) // land
+		grenade := newRocket(w.Player.Pos.Add(cp.Vector{X: tc.dx}), 0, RocketKindGrenade, 0, 30, w.space, 1)
+		w.rocketManager.rockets = append(w.rocketManager.rockets, grenade)
+
+		// Lying on the floor next to the player until its fuse burns out
+		stepWorld(w, Input{}, ticks(rocketKinds[RocketKindGrenade].fuseSec)-2)
+		if len(w.Rockets()) != 1 || w.Player.Health.Points != playerHealth {
+			t.Fatalf("%s: grenade exploded before its fuse burnt out", tc.name)
+		}
+		stepWorld(w, Input{}, 3)
+		if len(w.Rockets()) != 0 {
+			t.Fatalf("%s: grenade didn't explode by its fuse", tc.name)
+		}
+		if damaged := w.Player.Health.Points < playerHealth; damaged != tc.damage {
+			t.Errorf("%s: player damaged is %v, grenade at %v, player at %v", tc.name, damaged, grenade.Body.Position(), w.Player.Pos)
+		}
+	}
+}
+
+func TestPlasma(t *testing.T) {
+	w := loadTestWorld(t, testMap(""))
+	stepWorld(w, Input{}, ticks(0.5)) // land
+	plasma := newRocket(cp.Vector{X: 160, Y: 40}, 0, RocketKindPlasma, 0, 10, w.space, 1)
+	w.addMagnet(plasma.shape, rocketKinds[RocketKindPlasma].magnet)
+	w.rocketManager.rockets = append(w.rocketManager.rockets, plasma)
+
+	// Not pulled by the gun
+	for i := 0; i < ticks(1); i++ {
+		w.Step(&Input{Aim: plasma.Body.Position(), Gun: GunInputAttract})
+	}
+	if w.RayHitInfo.Shape != plasma.shape {
+		t.Fatal("gun ray doesn't hit the plasma")
+	}
+	if v := plasma.Body.Velocity(); math.Abs(v.X) > 1 {
+		t.Fatalf("plasma is pulled to the velocity %v", v)
+	}
+
+	// Fading out without exploding
+	for i := 0; i < ticks(rocketKinds[RocketKindPlasma].lifeSec); i++ {
+		w.Step(&Input{})
+		if countEvents(w, EventRocketHit) > 0 {
+			t.Fatal("plasma exploded")
+		}
+	}
+	if len(w.Rockets()) != 0 {
+		t.Fatal("plasma is still there after its life")
+	}
+}
+
+func countEvents(w *World, kind EventKind) int {
+	var n int
+	for _, event := range w.Events {
+		if event.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLaserHitsFirstBody(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		objects string
+		hits    bool
+	}{
+		{"player in sight", "", true},
+		{"wall in between", `
+  <object id="10" type="electricWall" x="120" y="100" width="8" height="100"/>`, false},
+	} {
+		w := loadTestWorld(t, testMap(tc.objects))
+		stepWorld(w, Input{}, ticks(0.5)) // land
+		start := cp.Vector{X: 240, Y: w.Player.Pos.Y}
+		w.fireLaser(start, math.Pi, 480, 30, 1)
+
+		if hits := w.Player.Health.Points == playerHealth-30; hits != tc.hits {
+			t.Errorf("%s: laser hits the player is %v", tc.name, hits)
+		}
+		lasers := w.Lasers()
+		if len(lasers) != 1 {
+			t.Fatalf("%s: %d lasers shown", tc.name, len(lasers))
+		}
+		if end := lasers[0].End.X; (end < w.Player.Pos.X+TileLength) != tc.hits || end >= start.X {
+			t.Errorf("%s: laser ends at %v", tc.name, lasers[0].End)
+		}
+
+		stepWorld(w, Input{}, ticks(LaserShowSec)+1)
+		if len(w.Lasers()) != 0 {
+			t.Errorf("%s: laser is still shown", tc.name)
+		}
+	}
+}
+
+func TestGrenadeBounces(t *testing.T) {
+	w := loadTestWorld(t, testMap(""))
+	grenade := newRocket(cp.Vector{X: 160, Y: 150}, 0, RocketKindGrenade, 0, 10, w.space, 1)
+	w.rocketManager.rockets = append(w.rocketManager.rockets, grenade)
+
+	// Falls on the floor and bounces up before its fuse burns out
+	bounced := false
+	for i := 0; i < ticks(rocketKinds[RocketKindGrenade].fuseSec)-1; i++ {
+		falling := grenade.Body.Velocity().Y > 0
+		w.Step(&Input{})
+		if falling && grenade.Body.Velocity().Y < 0 {
+			bounced = true
+			break
+		}
+	}
+	if !bounced {
+		t.Fatalf("grenade at %v didn't bounce off the floor", grenade.Body.Position())
+	}
+	if len(w.Rockets()) != 1 {
+		t.Fatal("grenade exploded hitting the floor")
+	}
+}
+
+func TestRocketsExplodeOnce(t *testing.T) {
+	w := loadTestWorld(t, testMap(""))
+	for _, x := range []float64{60, 160, 260} {
+		rocket := newRocket(cp.Vector{X: x, Y: 60}, 0, RocketKindRocket, 0, 10, w.space, 1)
+		rocket.detonate()
+		w.rocketManager.rockets = append(w.rocketManager.rockets, rocket)
+	}
+
+	w.Step(&Input{})
+	if n := countEvents(w, EventRocketHit); n != 3 {
+		t.Fatalf("%d of 3 rockets exploded", n)
+	}
+	if len(w.Rockets()) != 0 {
+		t.Fatalf("%d rockets are left after exploding", len(w.Rockets()))
+	}
+	w.Step(&Input{})
+	if n := countEvents(w, EventRocketHit); n != 0 {
+		t.Fatalf("%d rockets exploded again", n)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+// scheduledAction is an action waiting to be run after a delay in game time.
+type scheduledAction struct {
+	remainingSec float64
+	action       func()
+}
+
+// scheduler runs delayed actions inside the game loop.
+// Its clock only advances when it is updated, so it stops while the game is paused,
+// and its actions are dropped together with the game state on restart.
+type scheduler struct {
+	actions []*scheduledAction
+}
+
+// after schedules the action to be run once delaySec seconds of game time have passed.
+func (s *scheduler) after(delaySec float64, action func()) {
+	s.actions = append(s.actions, &scheduledAction{
+		remainingSec: delaySec,
+		action:       action,
+	})
+}
+
+// update advances the clock by dt seconds and runs the actions that are due, in the order they were scheduled.
+// Actions scheduled while running others wait at least until the next update.
+func (s *scheduler) update(dt float64) {
+	dueActions := make([]*scheduledAction, 0, len(s.actions))
+	waitingActions := s.actions[:0]
+	for _, act := range s.actions {
+		act.remainingSec -= dt
+		if act.remainingSec <= 0 {
+			dueActions = append(dueActions, act)
+		} else {
+			waitingActions = append(waitingActions, act)
+		}
+	}
+	// Clear the dropped tail so that finished closures can be collected
+	for i := len(waitingActions); i < len(s.actions); i++ {
+		s.actions[i] = nil
+	}
+	s.actions = waitingActions
+
+	for _, act := range dueActions {
+		act.action()
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/anilkonac/magrix/asset"
+)
+
+func TestSchedulerRunsActionsWhenDue(t *testing.T) {
+	var s scheduler
+	var ran []string
+	s.after(0.3, func() { ran = append(ran, "late") })
+	s.after(0.1, func() { ran = append(ran, "early") })
+	s.after(0.1, func() { ran = append(ran, "early2") })
+
+	s.update(0.05)
+	if len(ran) != 0 {
+		t.Fatalf("ran %v before they were due", ran)
+	}
+	s.update(0.05)
+	if want := []string{"early", "early2"}; !reflect.DeepEqual(ran, want) {
+		t.Fatalf("ran %v, want %v in the order they were scheduled", ran, want)
+	}
+	s.update(0.1)
+	if len(ran) != 2 {
+		t.Fatalf("ran %v before they were due", ran)
+	}
+	s.update(0.1)
+	if want := []string{"early", "early2", "late"}; !reflect.DeepEqual(ran, want) {
+		t.Fatalf("ran %v, want %v", ran, want)
+	}
+
+	s.update(1)
+	if len(ran) != 3 {
+		t.Fatalf("actions ran more than once: %v", ran)
+	}
+	if len(s.actions) != 0 {
+		t.Fatalf("%d actions left", len(s.actions))
+	}
+}
+
+func TestSchedulerActionsScheduledByActionsWait(t *testing.T) {
+	var s scheduler
+	ranInner := false
+	s.after(0, func() {
+		s.after(0, func() { ranInner = true })
+	})
+
+	s.update(0.1)
+	if ranInner {
+		t.Fatal("action scheduled while running another ran in the same update")
+	}
+	s.update(0.1)
+	if !ranInner {
+		t.Fatal("action scheduled while running another didn't run in the next update")
+	}
+}
+
+func TestSchedulerStopsWithWorld(t *testing.T) {
+	w := loadWorld(t, asset.MapTest)
+	ran := false
+	w.scheduler.after(0.5, func() { ran = true })
+
+	// The clock only advances when the world is stepped, like when the game isn't paused
+	stepWorld(w, Input{}, ticks(0.5)-2)
+	if ran {
+		t.Fatal("action ran early")
+	}
+	stepWorld(w, Input{}, 4)
+	if !ran {
+		t.Fatal("action didn't run")
+	}
+}
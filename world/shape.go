@@ -0,0 +1,162 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+const (
+	ellipseVertices = 16  // of the polygon that stands for an ellipse which isn't a circle
+	lineRadius      = 1.0 // of the segments of polylines
+)
+
+// objectShapes returns the shapes of a rectangle, polygon, polyline or ellipse object on the body.
+// Rectangles and convex polygons become polygon shapes, concave polygons are split into triangles,
+// polylines become chains of segments and ellipses become circles or polygons.
+func objectShapes(obj *tiled.Object, body *cp.Body) []*cp.Shape {
+	switch {
+	case len(obj.Polygons) > 0:
+		points := objectPoints(obj, *obj.Polygons[0].Points)
+		if isConvex(points) {
+			return []*cp.Shape{newPolyShape(body, points)}
+		}
+		var shapes []*cp.Shape
+		for _, triangle := range triangulate(points) {
+			shapes = append(shapes, newPolyShape(body, triangle))
+		}
+		return shapes
+
+	case len(obj.PolyLines) > 0:
+		points := objectPoints(obj, *obj.PolyLines[0].Points)
+		var shapes []*cp.Shape
+		for iPoint := 1; iPoint < len(points); iPoint++ {
+			shapes = append(shapes, cp.NewSegment(body, points[iPoint-1], points[iPoint], lineRadius))
+		}
+		return shapes
+
+	case len(obj.Ellipses) > 0:
+		halfW, halfH := obj.Width/2.0, obj.Height/2.0
+		center := objectPoint(obj, cp.Vector{X: halfW, Y: halfH})
+		if halfW == halfH {
+			return []*cp.Shape{cp.NewCircle(body, halfW, center)}
+		}
+		points := make([]cp.Vector, ellipseVertices)
+		for iPoint := range points {
+			angle := 2 * math.Pi * float64(iPoint) / ellipseVertices
+			points[iPoint] = objectPoint(obj, cp.Vector{X: halfW + halfW*math.Cos(angle), Y: halfH + halfH*math.Sin(angle)})
+		}
+		return []*cp.Shape{newPolyShape(body, points)}
+
+	case obj.Width == 0 || obj.Height == 0:
+		// A line drawn as a rectangle
+		return []*cp.Shape{cp.NewSegment(body, objectPoint(obj, cp.Vector{}), objectPoint(obj, cp.Vector{X: obj.Width, Y: obj.Height}), lineRadius)}
+
+	default:
+		return []*cp.Shape{newPolyShape(body, []cp.Vector{
+			objectPoint(obj, cp.Vector{}),
+			objectPoint(obj, cp.Vector{X: obj.Width}),
+			objectPoint(obj, cp.Vector{X: obj.Width, Y: obj.Height}),
+			objectPoint(obj, cp.Vector{Y: obj.Height}),
+		})}
+	}
+}
+
+func newPolyShape(body *cp.Body, points []cp.Vector) *cp.Shape {
+	return cp.NewPolyShape(body, len(points), points, cp.NewTransformIdentity(), 0)
+}
+
+// objectPoint returns the map position of a point relative to the object, which is rotated around its origin.
+func objectPoint(obj *tiled.Object, p cp.Vector) cp.Vector {
+	if obj.Rotation != 0 {
+		p = p.Rotate(cp.ForAngle(obj.Rotation * math.Pi / 180.0))
+	}
+	return p.Add(cp.Vector{X: obj.X, Y: obj.Y})
+}
+
+func objectPoints(obj *tiled.Object, points tiled.Points) []cp.Vector {
+	vectors := make([]cp.Vector, len(points))
+	for iPoint, point := range points {
+		vectors[iPoint] = objectPoint(obj, cp.Vector{X: point.X, Y: point.Y})
+	}
+	return vectors
+}
+
+// windingSign returns the sign of the signed area of the polygon, telling its winding.
+func windingSign(points []cp.Vector) float64 {
+	var area float64
+	for iPoint, p := range points {
+		area += p.Cross(points[(iPoint+1)%len(points)])
+	}
+	if area < 0 {
+		return -1
+	}
+	return 1
+}
+
+func isConvex(points []cp.Vector) bool {
+	sign := windingSign(points)
+	for iPoint := range points {
+		a, b, c := points[iPoint], points[(iPoint+1)%len(points)], points[(iPoint+2)%len(points)]
+		if b.Sub(a).Cross(c.Sub(b))*sign < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// triangulate splits a simple polygon into triangles by clipping its ears.
+func triangulate(points []cp.Vector) [][]cp.Vector {
+	sign := windingSign(points)
+	indices := make([]int, len(points))
+	for i := range indices {
+		indices[i] = i
+	}
+
+	var triangles [][]cp.Vector
+	for len(indices) > 3 {
+		earFound := false
+		for i := range indices {
+			a := points[indices[(i+len(indices)-1)%len(indices)]]
+			b := points[indices[i]]
+			c := points[indices[(i+1)%len(indices)]]
+			if b.Sub(a).Cross(c.Sub(b))*sign <= 0 {
+				continue // reflex vertex
+			}
+			if anyPointInTriangle(points, indices, a, b, c, sign) {
+				continue
+			}
+
+			triangles = append(triangles, []cp.Vector{a, b, c})
+			indices = append(indices[:i], indices[i+1:]...)
+			earFound = true
+			break
+		}
+		if !earFound {
+			break // not a simple polygon, keep the rest as it is
+		}
+	}
+
+	rest := make([]cp.Vector, len(indices))
+	for i, index := range indices {
+		rest[i] = points[index]
+	}
+	return append(triangles, rest)
+}
+
+// anyPointInTriangle reports whether any of the points other than the triangle's corners is inside the triangle.
+func anyPointInTriangle(points []cp.Vector, indices []int, a, b, c cp.Vector, sign float64) bool {
+	for _, index := range indices {
+		p := points[index]
+		if p == a || p == b || p == c {
+			continue
+		}
+		if b.Sub(a).Cross(p.Sub(a))*sign >= 0 && c.Sub(b).Cross(p.Sub(b))*sign >= 0 && a.Sub(c).Cross(p.Sub(c))*sign >= 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+// lShape is a concave polygon with the notch at its top right.
+var lShape = []cp.Vector{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 3}, {X: 4, Y: 3}, {X: 4, Y: 4}, {X: 0, Y: 4}}
+
+func reversed(points []cp.Vector) []cp.Vector {
+	r := make([]cp.Vector, len(points))
+	for i, p := range points {
+		r[len(points)-1-i] = p
+	}
+	return r
+}
+
+func area(points []cp.Vector) float64 {
+	return math.Abs(cp.AreaForPoly(len(points), points, 0))
+}
+
+func TestIsConvex(t *testing.T) {
+	square := []cp.Vector{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}}
+	for _, tc := range []struct {
+		name   string
+		points []cp.Vector
+		convex bool
+	}{
+		{"square", square, true},
+		{"reversed square", reversed(square), true},
+		{"L", lShape, false},
+		{"reversed L", reversed(lShape), false},
+	} {
+		if convex := isConvex(tc.points); convex != tc.convex {
+			t.Errorf("%s: convex is %v", tc.name, convex)
+		}
+	}
+}
+
+func TestTriangulate(t *testing.T) {
+	for _, points := range [][]cp.Vector{lShape, reversed(lShape)} {
+		triangles := triangulate(points)
+		if len(triangles) != len(points)-2 {
+			t.Fatalf("%d triangles of %d points", len(triangles), len(points))
+		}
+		var sum float64
+		for _, triangle := range triangles {
+			if len(triangle) != 3 {
+				t.Fatalf("triangle with %d points", len(triangle))
+			}
+			sum += area(triangle)
+		}
+		if want := area(points); math.Abs(sum-want) > 1e-9 {
+			t.Errorf("triangles cover an area of %v, not %v", sum, want)
+		}
+	}
+}
+
+func tiledPoints(points []cp.Vector) *tiled.Points {
+	var tp tiled.Points
+	for _, p := range points {
+		tp = append(tp, &tiled.Point{X: p.X, Y: p.Y})
+	}
+	return &tp
+}
+
+// containsPoint reports whether any of the shapes contains the point.
+func containsPoint(shapes []*cp.Shape, p cp.Vector) bool {
+	for _, shape := range shapes {
+		shape.CacheBB() // transforms the vertices
+		if shape.PointQuery(p).Distance < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestObjectShapes(t *testing.T) {
+	const x, y = 100, 50
+	l := make([]cp.Vector, len(lShape))
+	for i, p := range lShape {
+		l[i] = p.Mult(10)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		obj       tiled.Object
+		numShapes int
+		inside    []cp.Vector
+		outside   []cp.Vector
+	}{
+		{
+			name: "rectangle", obj: tiled.Object{Width: 20, Height: 10}, numShapes: 1,
+			inside: []cp.Vector{{X: 19, Y: 9}}, outside: []cp.Vector{{X: 21, Y: 5}},
+		},
+		{
+			name: "rotated rectangle", obj: tiled.Object{Width: 20, Height: 10, Rotation: 90}, numShapes: 1,
+			inside: []cp.Vector{{X: -9, Y: 19}}, outside: []cp.Vector{{X: 9, Y: 5}},
+		},
+		{
+			name: "concave polygon", obj: tiled.Object{Polygons: []*tiled.Polygon{{Points: tiledPoints(l)}}}, numShapes: 4,
+			inside: []cp.Vector{{X: 5, Y: 5}, {X: 35, Y: 35}}, outside: []cp.Vector{{X: 25, Y: 15}},
+		},
+		{
+			name: "circle", obj: tiled.Object{Width: 20, Height: 20, Ellipses: []*tiled.Ellipse{{}}}, numShapes: 1,
+			inside: []cp.Vector{{X: 10, Y: 1}}, outside: []cp.Vector{{X: 1, Y: 1}},
+		},
+		{
+			name: "ellipse", obj: tiled.Object{Width: 40, Height: 20, Ellipses: []*tiled.Ellipse{{}}}, numShapes: 1,
+			inside: []cp.Vector{{X: 38, Y: 10}}, outside: []cp.Vector{{X: 20, Y: 21}},
+		},
+		{
+			name: "polyline", obj: tiled.Object{PolyLines: []*tiled.PolyLine{{Points: tiledPoints(l)}}}, numShapes: len(l) - 1,
+			inside: []cp.Vector{{X: 20, Y: 30}}, outside: []cp.Vector{{X: 5, Y: 5}},
+		},
+	} {
+		obj := tc.obj
+		obj.X, obj.Y = x, y
+		shapes := objectShapes(&obj, cp.NewStaticBody())
+		if len(shapes) != tc.numShapes {
+			t.Errorf("%s: %d shapes", tc.name, len(shapes))
+			continue
+		}
+		for _, p := range tc.inside {
+			if !containsPoint(shapes, p.Add(cp.Vector{X: x, Y: y})) {
+				t.Errorf("%s: %v isn't inside", tc.name, p)
+			}
+		}
+		for _, p := range tc.outside {
+			if containsPoint(shapes, p.Add(cp.Vector{X: x, Y: y})) {
+				t.Errorf("%s: %v is inside", tc.name, p)
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+const terminalDelaySec = 2.0
+
+func init() {
+	registerEntity(ObjectTypeTerminal, newTerminal)
+}
+
+// Terminal triggers its targets a while after the player activates it.
+type Terminal struct {
+	entityBase
+	Color     string // one of the ColorName values
+	Working   bool   // activated, but its targets aren't triggered yet
+	Triggered bool
+	shapes    []*cp.Shape
+	delaySec  float64
+	lives     int // given to the player when triggered
+	targets   []uint32
+}
+
+func newTerminal(obj *tiled.Object, w *World) Entity {
+	var shapes []*cp.Shape
+	if obj.Properties.GetBool(propertyBlocking) {
+		shapes = w.addWallShapes(obj)
+	}
+
+	return &Terminal{
+		entityBase: newEntityBase(obj),
+		shapes:     shapes,
+		Color:      obj.Properties.GetString(propertyColor),
+		delaySec:   floatProperty(obj, propertyDelay, terminalDelaySec),
+		lives:      obj.Properties.GetInt(propertyLives),
+		targets:    objectTargets(obj),
+	}
+}
+
+// interact starts the terminal's work.
+func (t *Terminal) interact(w *World) {
+	if t.Working || t.Triggered {
+		return
+	}
+	t.Working = true
+
+	w.scheduler.after(t.delaySec, func() {
+		t.Working = false
+		t.trigger(w)
+	})
+}
+
+func (t *Terminal) trigger(w *World) {
+	if t.Triggered {
+		return
+	}
+	t.Triggered = true
+	w.Player.NumLives += t.lives
+	w.triggerTargets(t.targets)
+}
+
+func (t *Terminal) saved() bool {
+	return t.Triggered
+}
+
+// restore marks the terminal triggered. Its targets are restored on their own.
+func (t *Terminal) restore(w *World) {
+	t.Triggered = true
+}
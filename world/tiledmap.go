@@ -0,0 +1,138 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/lafriks/go-tiled"
+)
+
+// Names of the object groups (object layers) in Tiled maps
+const (
+	groupNameWalls       = "Walls"
+	groupNamePlayerStart = "PlayerStart"
+	groupNameEnemies     = "Enemies"
+)
+
+// Types (classes) of the objects in Tiled maps
+const (
+	objectTypePlayerStart = "playerStart"
+	objectTypeEnemy       = "enemy"
+)
+
+// Types of the objects that are created as entities, in any object group
+const (
+	ObjectTypeElectricWall  = "electricWall"
+	ObjectTypeTerminal      = "terminal"
+	ObjectTypeButton        = "button"
+	ObjectTypeCheckpoint    = "checkpoint"
+	ObjectTypeCrate         = "crate"
+	ObjectTypePressurePlate = "pressurePlate"
+	ObjectTypePlatform      = "platform"
+)
+
+// Custom object properties in Tiled maps
+const (
+	propertyColor           = "color"
+	propertyTurnedLeft      = "turnedLeft"
+	propertyBlocking        = "blocking"
+	propertyTarget          = "target" // prefix of the properties referring to the objects to trigger
+	propertyDelay           = "delay"
+	propertyLives           = "lives"
+	propertyHealth          = "health"
+	propertyDamageThreshold = "damageThreshold" // collision impulse over which damage is dealt
+	propertyMetal           = "metal"
+	propertyMagnetStrength  = "magnetStrength"
+	propertyMovable         = "movable" // pulled and pushed by the gun
+	propertyMass            = "mass"
+	propertyPath            = "path" // polyline object followed by a platform or an enemy
+	propertyMoving          = "moving"
+	propertyLoop            = "loop"
+	propertyStationary      = "stationary"  // of enemies that never walk
+	propertyVisionAngle     = "visionAngle" // degrees
+	propertyVisionRange     = "visionRange"
+	propertyArchetype       = "archetype" // of enemies, a name in the enemies data file
+	propertySpeed           = "speed"
+	propertySolid           = "solid"         // of tileset tiles that walls are generated from
+	propertyGenerateWalls   = "generateWalls" // of maps whose walls are generated from the platforms layer
+)
+
+// Type of the properties referring to other objects
+const propertyTypeObject = "object"
+
+// Values of the color property
+const (
+	ColorNameGreen  = "green"
+	ColorNameBlue   = "blue"
+	ColorNameOrange = "orange"
+)
+
+// floatProperty returns the number value of the property of the object, or def if the object doesn't have it.
+// Both int and float properties are accepted.
+func floatProperty(obj *tiled.Object, name string, def float64) float64 {
+	values := obj.Properties.Get(name)
+	if len(values) == 0 {
+		return def
+	}
+	value, err := strconv.ParseFloat(values[0], 64)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// boolProperty returns the bool value of the property of the object, or def if the object doesn't have it.
+func boolProperty(obj *tiled.Object, name string, def bool) bool {
+	if len(obj.Properties.Get(name)) == 0 {
+		return def
+	}
+	return obj.Properties.GetBool(name)
+}
+
+// findObjectGroup returns the object group with the given name, or nil if the map doesn't have one.
+func findObjectGroup(gameMap *tiled.Map, name string) *tiled.ObjectGroup {
+	for _, group := range gameMap.ObjectGroups {
+		if group.Name == name {
+			return group
+		}
+	}
+	return nil
+}
+
+// objectGroup returns the object group with the given name.
+func objectGroup(gameMap *tiled.Map, name string) (*tiled.ObjectGroup, error) {
+	if group := findObjectGroup(gameMap, name); group != nil {
+		return group, nil
+	}
+	return nil, fmt.Errorf("map: object group %q not found", name)
+}
+
+// objectType returns the class of the object. Maps saved before Tiled 1.9 store it as type.
+func objectType(obj *tiled.Object) string {
+	if obj.Class != "" {
+		return obj.Class
+	}
+	return obj.Type
+}
+
+// objectsOfType returns all objects of the given type in the group.
+func objectsOfType(group *tiled.ObjectGroup, typ string) []*tiled.Object {
+	var objects []*tiled.Object
+	for _, obj := range group.Objects {
+		if objectType(obj) == typ {
+			objects = append(objects, obj)
+		}
+	}
+	return objects
+}
+
+// objectOfType returns the first object of the given type in the group.
+func objectOfType(group *tiled.ObjectGroup, typ string) (*tiled.Object, error) {
+	objects := objectsOfType(group, typ)
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("map: no %q object in group %q", typ, group.Name)
+	}
+	return objects[0], nil
+}
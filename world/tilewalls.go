@@ -0,0 +1,110 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lafriks/go-tiled"
+)
+
+// LayerNamePlatforms is the name of the tile layer with the walls and platforms of the map.
+const LayerNamePlatforms = "Platforms"
+
+// tileWalls returns rectangle objects covering the solid tiles of the platforms layer, which are the tiles
+// whose tileset tiles have the solid property. Neighboring solid tiles are merged greedily into rectangles
+// as wide as possible, then as tall as possible. Tiles covered by platform objects are left out.
+func tileWalls(gameMap *tiled.Map) ([]*tiled.Object, error) {
+	var layer *tiled.Layer
+	for _, l := range gameMap.Layers {
+		if l.Name == LayerNamePlatforms {
+			layer = l
+		}
+	}
+	if layer == nil {
+		return nil, fmt.Errorf("map: tile layer %q not found", LayerNamePlatforms)
+	}
+
+	width, height := gameMap.Width, gameMap.Height
+	solid := make([]bool, width*height)
+	for iTile, tile := range layer.Tiles {
+		if iTile < len(solid) {
+			solid[iTile] = isSolidTile(tile)
+		}
+	}
+
+	// The tiles at the start of platforms move with them
+	for _, group := range gameMap.ObjectGroups {
+		for _, obj := range objectsOfType(group, ObjectTypePlatform) {
+			left, right := tileRange(obj.X, obj.Width, gameMap.TileWidth, width)
+			top, bottom := tileRange(obj.Y, obj.Height, gameMap.TileHeight, height)
+			for row := top; row < bottom; row++ {
+				for col := left; col < right; col++ {
+					solid[row*width+col] = false
+				}
+			}
+		}
+	}
+
+	var walls []*tiled.Object
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if !solid[y*width+x] {
+				continue
+			}
+
+			w := 1
+			for x+w < width && solid[y*width+x+w] {
+				w++
+			}
+			h := 1
+			for y+h < height && isRowSolid(solid[(y+h)*width+x:(y+h)*width+x+w]) {
+				h++
+			}
+
+			// Take the rectangle's tiles so they aren't merged again
+			for row := y; row < y+h; row++ {
+				for col := x; col < x+w; col++ {
+					solid[row*width+col] = false
+				}
+			}
+
+			walls = append(walls, &tiled.Object{
+				X:      float64(x * gameMap.TileWidth),
+				Y:      float64(y * gameMap.TileHeight),
+				Width:  float64(w * gameMap.TileWidth),
+				Height: float64(h * gameMap.TileHeight),
+			})
+		}
+	}
+
+	return walls, nil
+}
+
+// tileRange returns the range of the tiles, at most numTiles, covered by the span of pixels starting at pos.
+func tileRange(pos, length float64, tileLength, numTiles int) (start, end int) {
+	start = int(math.Max(math.Floor(pos/float64(tileLength)), 0))
+	end = int(math.Min(math.Ceil((pos+length)/float64(tileLength)), float64(numTiles)))
+	return
+}
+
+func isSolidTile(tile *tiled.LayerTile) bool {
+	if tile.IsNil() {
+		return false
+	}
+	tilesetTile, err := tile.Tileset.GetTilesetTile(tile.ID)
+	if err != nil {
+		return false
+	}
+	return tilesetTile.Properties.GetBool(propertySolid)
+}
+
+func isRowSolid(row []bool) bool {
+	for _, solid := range row {
+		if !solid {
+			return false
+		}
+	}
+	return true
+}
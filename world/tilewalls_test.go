@@ -0,0 +1,94 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lafriks/go-tiled"
+)
+
+// tileMap returns a 6x4 map of 16 pixel tiles, where tile 1 is solid and tile 2 isn't, with the objects.
+func tileMap(csv, objects string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.8" orientation="orthogonal" renderorder="right-down" width="6" height="4" tilewidth="16" tileheight="16" infinite="0" nextlayerid="3" nextobjectid="100">
+ <tileset firstgid="1" name="tiles" tilewidth="16" tileheight="16" tilecount="2" columns="2">
+  <tile id="0"><properties><property name="solid" type="bool" value="true"/></properties></tile>
+ </tileset>
+ <layer id="1" name="Platforms" width="6" height="4">
+  <data encoding="csv">` + csv + `</data>
+ </layer>
+ <objectgroup id="2" name="Objects">` + objects + `
+ </objectgroup>
+</map>`
+}
+
+type rect struct {
+	x, y, width, height float64
+}
+
+// tileWallRects returns the rectangles of the walls generated from the map.
+func tileWallRects(t *testing.T, tmx string) []rect {
+	t.Helper()
+	gameMap, err := tiled.LoadReader("", strings.NewReader(tmx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	walls, err := tileWalls(gameMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rects := make([]rect, len(walls))
+	for iWall, wall := range walls {
+		rects[iWall] = rect{wall.X, wall.Y, wall.Width, wall.Height}
+	}
+	return rects
+}
+
+func TestTileWallsMerge(t *testing.T) {
+	rects := tileWallRects(t, tileMap(`
+1,1,0,0,2,1,
+1,1,0,0,0,1,
+1,1,1,1,0,0,
+1,1,1,1,1,1`, ""))
+
+	want := []rect{
+		{0, 0, 32, 64},  // the left block, as tall as it goes
+		{80, 0, 16, 32}, // the right column, the non-solid tile left out
+		{32, 32, 32, 32},
+		{64, 48, 32, 16},
+	}
+	if len(rects) != len(want) {
+		t.Fatalf("walls are %+v", rects)
+	}
+	for iRect, rect := range rects {
+		if rect != want[iRect] {
+			t.Errorf("wall %d is %+v, not %+v", iRect, rect, want[iRect])
+		}
+	}
+}
+
+func TestTileWallsSkipPlatforms(t *testing.T) {
+	rects := tileWallRects(t, tileMap(`
+0,0,0,0,0,0,
+0,1,1,0,0,0,
+0,0,0,0,0,0,
+1,1,1,1,1,1`, `
+  <object id="1" type="platform" x="16" y="16" width="32" height="16"/>`))
+
+	want := rect{0, 48, 96, 16}
+	if len(rects) != 1 || rects[0] != want {
+		t.Fatalf("walls are %+v, not only the floor", rects)
+	}
+}
+
+func TestTileWallsWithoutLayer(t *testing.T) {
+	gameMap, err := tiled.LoadReader("", strings.NewReader(testMap("")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tileWalls(gameMap); err == nil {
+		t.Fatal("walls are generated without a platforms layer")
+	}
+}
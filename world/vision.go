@@ -0,0 +1,78 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+
+	"github.com/jakecoffman/cp"
+)
+
+const (
+	enemyAlertSec         = 0.75 // to notice the player standing at the far end of the cone
+	enemyCalmSec          = 3.0  // to forget the player after losing sight of it
+	enemyAlertDistanceMul = 3.0  // the player is noticed this many times faster right in front of the enemy
+)
+
+// Walls and crates block the sight of enemies, so the player can hide behind them
+var filterVision = cp.NewShapeFilter(cp.NO_GROUP, cp.ALL_CATEGORIES, categoryWall|categoryCrate)
+
+// vision is the cone an enemy sees in front of itself.
+type vision struct {
+	halfAngle float64 // radians
+	distance  float64
+}
+
+func newVision(angleDeg, distance float64) vision {
+	return vision{
+		halfAngle: angleDeg / 2.0 * cp.RadianConst,
+		distance:  distance,
+	}
+}
+
+// look updates the alertness of the enemy by whether the player is in sight.
+// The enemy sees the player only after it has become fully alert.
+func (e *Enemy) look(w *World) {
+	distance, inSight := e.sight(w)
+	if inSight {
+		closeness := 1.0 - distance/e.vision.distance
+		e.Alertness += DeltaTimeSec / enemyAlertSec * (1.0 + closeness*(enemyAlertDistanceMul-1.0))
+	} else {
+		e.Alertness -= DeltaTimeSec / enemyCalmSec
+	}
+	e.Alertness = cp.Clamp01(e.Alertness)
+	e.seesPlayer = inSight && e.Alertness >= 1.0
+}
+
+// alert makes the enemy fully alert, like when it is attacked.
+func (e *Enemy) alert() {
+	e.Alertness = 1.0
+}
+
+// sight returns the distance to the player and whether any of the player's head, center and feet is in the vision cone
+// with nothing blocking the view.
+func (e *Enemy) sight(w *World) (distance float64, inSight bool) {
+	if w.Player.NumLives <= 0 {
+		return 0, false
+	}
+
+	eye := e.Body.Position()
+	forward := cp.ForAngle(e.Body.Angle())
+	if e.TurnedLeft {
+		forward.X = -forward.X // mirrored like the line of fire
+	}
+
+	halfHeight := playerHeightTile * TileLength / 2.0
+	for _, offsetY := range [...]float64{-halfHeight * 0.8, 0, halfHeight * 0.8} {
+		point := w.Player.Pos.Add(cp.Vector{Y: offsetY})
+		toPoint := point.Sub(eye)
+		distance = toPoint.Length()
+		if distance > e.vision.distance || math.Acos(cp.Clamp(toPoint.Normalize().Dot(forward), -1, 1)) > e.vision.halfAngle {
+			continue
+		}
+		if w.space.SegmentQueryFirst(eye, point, 0, filterVision).Shape == nil {
+			return distance, true
+		}
+	}
+	return distance, false
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"testing"
+
+	"github.com/jakecoffman/cp"
+)
+
+func TestWallBlocksVision(t *testing.T) {
+	tmx := withEnemies(testMap(`
+  <object id="11" type="electricWall" x="120" y="100" width="8" height="100"/>`), `
+  <object id="10" type="enemy" x="240" y="180">
+   <properties>
+    <property name="turnedLeft" type="bool" value="true"/>
+    <property name="stationary" type="bool" value="true"/>
+   </properties>
+  </object>`)
+	w := loadTestWorld(t, tmx)
+	enemy := w.Enemies[0]
+
+	stepWorld(w, Input{}, ticks(2))
+	if enemy.Alertness > 0 || enemy.State != EnemyStateIdle {
+		t.Fatalf("enemy behind the wall noticed the player: alertness %.2f, state %d", enemy.Alertness, enemy.State)
+	}
+
+	w.triggerTargets([]uint32{11})
+	stepWorld(w, Input{}, ticks(enemyAlertSec)+1)
+	if enemy.State != EnemyStateChase {
+		t.Fatalf("enemy doesn't chase the player in sight: alertness %.2f, state %d", enemy.Alertness, enemy.State)
+	}
+}
+
+func TestEnemyDoesNotSeeBehind(t *testing.T) {
+	tmx := withEnemies(testMap(""), `
+  <object id="10" type="enemy" x="240" y="180">
+   <properties>
+    <property name="stationary" type="bool" value="true"/>
+   </properties>
+  </object>`)
+	w := loadTestWorld(t, tmx)
+
+	stepWorld(w, Input{}, ticks(2))
+	if enemy := w.Enemies[0]; enemy.Alertness > 0 {
+		t.Fatalf("enemy turned right noticed the player on its left: alertness %.2f", enemy.Alertness)
+	}
+}
+
+func TestTiltedEnemyTurnedLeftSees(t *testing.T) {
+	tmx := withEnemies(testMap(""), `
+  <object id="10" type="enemy" x="240" y="180">
+   <properties>
+    <property name="turnedLeft" type="bool" value="true"/>
+   </properties>
+  </object>`)
+	w := loadTestWorld(t, tmx)
+	stepWorld(w, Input{}, ticks(0.5)) // land
+	enemy := w.Enemies[0]
+
+	// Above the player, tilted so that it looks down to the left like its line of fire.
+	// With its forward turned around instead of mirrored, it would look up to the left and miss the player.
+	enemy.Body.SetPosition(cp.Vector{X: 240, Y: 100})
+	enemy.Body.SetAngle(0.8)
+	if _, inSight := enemy.sight(w); !inSight {
+		t.Fatalf("tilted enemy turned left doesn't see the player at %v below its line of sight", w.Player.Pos)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+// target is an object that can be triggered by the objects targeting it.
+type target interface {
+	trigger(w *World)
+}
+
+// holdable is an object that stays triggered only while it is held, like an electric wall targeted by a pressure plate.
+type holdable interface {
+	hold(w *World, held bool)
+}
+
+// objectTargets returns the IDs of the objects the object targets.
+// Targets are object properties named target, or starting with it like target2, so that an object can have many.
+func objectTargets(obj *tiled.Object) []uint32 {
+	var targets []uint32
+	for _, property := range obj.Properties {
+		if property.Type != propertyTypeObject || !strings.HasPrefix(property.Name, propertyTarget) {
+			continue
+		}
+		if id := parseObjectID(property.Value); id != 0 {
+			targets = append(targets, id)
+		}
+	}
+	return targets
+}
+
+// parseObjectID returns the ID in the value of an object property, or 0 if it is unset.
+func parseObjectID(value string) uint32 {
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(id)
+}
+
+// triggerTargets triggers the entities with the IDs. IDs of objects that can't be triggered are ignored.
+func (w *World) triggerTargets(ids []uint32) {
+	for _, id := range ids {
+		if t, ok := w.entityByID[id].(target); ok {
+			t.trigger(w)
+		}
+	}
+}
+
+// holdTargets holds or releases the entities with the IDs. Entities that can't be held are triggered when held.
+func (w *World) holdTargets(ids []uint32, held bool) {
+	for _, id := range ids {
+		switch t := w.entityByID[id].(type) {
+		case holdable:
+			t.hold(w, held)
+		case target:
+			if held {
+				t.trigger(w)
+			}
+		}
+	}
+}
+
+// objectPath returns the map positions of the points of the polyline object the object's path property refers to,
+// or nil if it has none.
+func (w *World) objectPath(obj *tiled.Object) []cp.Vector {
+	ids := obj.Properties.Get(propertyPath)
+	if len(ids) == 0 {
+		return nil
+	}
+	objPath := w.objectByID[parseObjectID(ids[0])]
+	if objPath == nil || len(objPath.PolyLines) == 0 || objPath.PolyLines[0].Points == nil {
+		return nil
+	}
+	return objectPoints(objPath, *objPath.PolyLines[0].Points)
+}
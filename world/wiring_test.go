@@ -0,0 +1,95 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lafriks/go-tiled"
+)
+
+const wiringObjects = `
+  <object id="10" type="terminal" x="60" y="184" width="16" height="16">
+   <properties>
+    <property name="target" type="object" value="20"/>
+    <property name="target2" type="object" value="21"/>
+   </properties>
+  </object>
+  <object id="11" type="terminal" x="100" y="184" width="16" height="16">
+   <properties>
+    <property name="target" type="object" value="12"/>
+   </properties>
+  </object>
+  <object id="12" type="terminal" x="140" y="184" width="16" height="16">
+   <properties>
+    <property name="target" type="object" value="22"/>
+    <property name="lives" type="int" value="1"/>
+   </properties>
+  </object>
+  <object id="13" type="terminal" x="180" y="184" width="16" height="16">
+   <properties>
+    <property name="target" type="object" value="999"/>
+   </properties>
+  </object>
+  <object id="20" type="electricWall" x="200" y="100" width="8" height="100"/>
+  <object id="21" type="electricWall" x="220" y="100" width="8" height="100"/>
+  <object id="22" type="electricWall" x="240" y="100" width="8" height="100"/>`
+
+func TestTerminalTriggersAllTargets(t *testing.T) {
+	w := loadTestWorld(t, testMap(wiringObjects))
+	w.triggerTargets([]uint32{10})
+
+	for _, id := range []uint32{20, 21} {
+		if !w.entityByID[id].(*ElectricWall).Removed {
+			t.Errorf("wall %d isn't removed by the terminal targeting it", id)
+		}
+	}
+	if w.entityByID[22].(*ElectricWall).Removed {
+		t.Error("wall 22 is removed by a terminal not targeting it")
+	}
+}
+
+func TestTargetChain(t *testing.T) {
+	w := loadTestWorld(t, testMap(wiringObjects))
+	lives := w.Player.NumLives
+
+	// A terminal triggering another terminal, which removes the wall
+	w.triggerTargets([]uint32{11})
+	if !w.entityByID[12].(*Terminal).Triggered {
+		t.Fatal("terminal isn't triggered by the terminal targeting it")
+	}
+	if !w.entityByID[22].(*ElectricWall).Removed {
+		t.Fatal("wall at the end of the chain isn't removed")
+	}
+	if w.Player.NumLives != lives+1 {
+		t.Fatalf("player has %d lives, not %d given by the chained terminal", w.Player.NumLives, lives+1)
+	}
+
+	// Triggered terminals aren't triggered again
+	w.triggerTargets([]uint32{11, 12})
+	if w.Player.NumLives != lives+1 {
+		t.Fatalf("player has %d lives after triggering the chain again", w.Player.NumLives)
+	}
+}
+
+func TestMissingTargetIsIgnored(t *testing.T) {
+	w := loadTestWorld(t, testMap(wiringObjects))
+	w.triggerTargets([]uint32{13, 999})
+	if !w.entityByID[13].(*Terminal).Triggered {
+		t.Fatal("terminal with a missing target isn't triggered")
+	}
+}
+
+func TestObjectTargets(t *testing.T) {
+	obj := &tiled.Object{Properties: tiled.Properties{
+		{Name: "target", Type: propertyTypeObject, Value: "5"},
+		{Name: "target2", Type: propertyTypeObject, Value: "0"}, // unset
+		{Name: "targetName", Type: "string", Value: "7"},
+		{Name: "color", Value: "blue"},
+		{Name: "target3", Type: propertyTypeObject, Value: "8"},
+	}}
+	if targets, want := objectTargets(obj), []uint32{5, 8}; !reflect.DeepEqual(targets, want) {
+		t.Fatalf("targets are %v, not %v", targets, want)
+	}
+}
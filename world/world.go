@@ -0,0 +1,339 @@
+// Copyright 2022 Anıl Konaç
+
+// Package world simulates a level: the physics space, the player, enemies, rockets and the objects to interact with.
+// It doesn't depend on ebiten, so a level can be stepped without a display or an audio device, e.g. in tests.
+// Drawing and sounds are left to the caller, which reads the state of the world and the events of each step.
+package world
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+const (
+	DeltaTimeSec = 1.0 / 60.0
+	TileLength   = 16
+	MapWidth     = 960
+	MapHeight    = 960
+)
+
+const (
+	wallElasticity = 1 // elasticities are multiplied, so what hits a wall bounces by its own elasticity
+	wallFriction   = 1
+	// spaceIterations      = 10
+)
+
+const interactionRadiusTile = 1.25
+
+// World is a level being played.
+type World struct {
+	Player     *Player
+	Enemies    []*Enemy
+	Entities   []Entity            // objects created from the map through the entity registry
+	RayHitInfo cp.SegmentQueryInfo // where the player's gun ray hits
+	Completed  bool                // whether the level is completed
+
+	// Events happened in the last step
+	Events []Event
+
+	space         *cp.Space
+	rocketManager rocketManager
+	scheduler     scheduler
+	checkpoint    *Checkpoint              // last checkpoint reached
+	entityByID    map[uint32]Entity        // entities by the IDs of their objects
+	objectByID    map[uint32]*tiled.Object // objects of the map by their IDs, for objects referring to others
+	magnets       map[*cp.Shape]magnet
+}
+
+// New builds a world from the objects of the map.
+func New(gameMap *tiled.Map) (*World, error) {
+	space := cp.NewSpace()
+	// space.Iterations = spaceIterations
+	space.SetGravity(cp.Vector{X: 0, Y: gravity})
+
+	w := &World{
+		space: space,
+		rocketManager: rocketManager{
+			space: space,
+		},
+		entityByID: make(map[uint32]Entity),
+	}
+	w.addCollisionHandlers()
+	if err := w.loadMap(gameMap); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *World) loadMap(gameMap *tiled.Map) error {
+	w.objectByID = make(map[uint32]*tiled.Object)
+	for _, group := range gameMap.ObjectGroups {
+		for _, obj := range group.Objects {
+			w.objectByID[obj.ID] = obj
+		}
+	}
+
+	// Walls are drawn in the walls group, generated from the solid tiles of the platforms layer, or both
+	generateWalls := gameMap.Properties != nil && gameMap.Properties.GetBool(propertyGenerateWalls)
+	if generateWalls {
+		walls, err := tileWalls(gameMap)
+		if err != nil {
+			return err
+		}
+		w.addWalls(walls)
+	}
+	if groupWalls := findObjectGroup(gameMap, groupNameWalls); groupWalls != nil {
+		w.addWalls(groupWalls.Objects)
+	} else if !generateWalls {
+		return fmt.Errorf("map: object group %q not found", groupNameWalls)
+	}
+
+	// Add the player
+	groupPlayerStart, err := objectGroup(gameMap, groupNamePlayerStart)
+	if err != nil {
+		return err
+	}
+	objPlayerStart, err := objectOfType(groupPlayerStart, objectTypePlayerStart)
+	if err != nil {
+		return err
+	}
+	w.Player = newPlayer(cp.Vector{X: objPlayerStart.X, Y: objPlayerStart.Y}, w.space,
+		newHealth(objPlayerStart, playerHealth, playerDamageThreshold, playerDamagePerImpulse))
+
+	// Add enemies (optional)
+	if groupEnemies := findObjectGroup(gameMap, groupNameEnemies); groupEnemies != nil {
+		for iEnemy, objEnemy := range objectsOfType(groupEnemies, objectTypeEnemy) {
+			archetypeName := defaultEnemyArchetype
+			if values := objEnemy.Properties.Get(propertyArchetype); len(values) > 0 && values[0] != "" {
+				archetypeName = values[0]
+			}
+			archetype := enemyArchetypes[archetypeName]
+			if archetype == nil {
+				return fmt.Errorf("map: enemy %d has unknown archetype %q", objEnemy.ID, archetypeName)
+			}
+
+			group := uint(iEnemy + 1) // each enemy with its rockets
+			health := newHealth(objEnemy, archetype.Health, archetype.DamageThreshold, archetype.DamagePerImpulse)
+			enemy := newEnemy(cp.Vector{X: objEnemy.X, Y: objEnemy.Y}, w.space, archetype, objEnemy.Properties.GetBool(propertyTurnedLeft), health, group)
+			enemy.stationary = boolProperty(objEnemy, propertyStationary, archetype.AI.Stationary)
+			enemy.vision = newVision(
+				floatProperty(objEnemy, propertyVisionAngle, archetype.AI.VisionAngle),
+				floatProperty(objEnemy, propertyVisionRange, archetype.AI.VisionRange),
+			)
+			enemy.waypoints = w.objectPath(objEnemy)
+			if len(enemy.waypoints) > 0 {
+				enemy.State = EnemyStatePatrol
+			}
+			w.addMagnet(enemy.shape, objectMagnet(objEnemy, magnet{
+				metal:    archetype.Metal,
+				strength: archetype.MagnetStrength,
+				movable:  true,
+			}))
+			w.Enemies = append(w.Enemies, enemy)
+		}
+	}
+
+	// Add terminals, electric walls, checkpoints and whatever else has a registered entity kind
+	w.addEntities(gameMap)
+	if !w.hasEntity(ObjectTypeButton) {
+		return fmt.Errorf("map: no %q object", ObjectTypeButton)
+	}
+
+	return nil
+}
+
+// addWallShapes adds the static shapes of the object to the space, with the magnetic properties of the object.
+func (w *World) addWallShapes(obj *tiled.Object) []*cp.Shape {
+	shapes := objectShapes(obj, w.space.StaticBody)
+	m := newMagnet(obj, false)
+	for _, shape := range shapes {
+		shape.SetCollisionType(collisionTypeWall)
+		shape.SetFilter(filterWall)
+		w.space.AddShape(shape)
+		w.addMagnet(shape, m)
+	}
+	return shapes
+}
+
+func (w *World) addWalls(wallObjects []*tiled.Object) {
+	for _, obj := range wallObjects {
+		for _, shape := range w.addWallShapes(obj) {
+			shape.SetElasticity(wallElasticity)
+			shape.SetFriction(wallFriction)
+		}
+	}
+}
+
+// Rockets returns the rockets flying in the world.
+func (w *World) Rockets() []*Rocket {
+	return w.rocketManager.rockets
+}
+
+// Lasers returns the lasers fired lately.
+func (w *World) Lasers() []*Laser {
+	return w.rocketManager.lasers
+}
+
+// hitBody damages the player or the enemy with the body, if it is one, hit by a rocket or a laser.
+func (w *World) hitBody(body *cp.Body, damage float64) {
+	if body == w.Player.Body {
+		w.Player.damage(damage)
+		return
+	}
+	for _, enemy := range w.Enemies {
+		if body == enemy.Body && enemy.IsAlive {
+			enemy.damage(damage) // killed in its update
+			enemy.alert()
+		}
+	}
+}
+
+// Step advances the world by one tick of DeltaTimeSec with the given input.
+func (w *World) Step(inp *Input) {
+	w.Events = w.Events[:0]
+
+	w.scheduler.update(DeltaTimeSec)
+	w.space.Step(DeltaTimeSec)
+
+	w.rayCast()
+	for _, rocket := range w.rocketManager.update(w) {
+		w.explode(rocket.Body.Position(), rocketBlastRadius, rocket.damage, rocketBlastImpulse, nil, rocket.hitBody)
+	}
+
+	// Update player and player's gun
+	var rayHitMagnet magnet
+	if w.RayHitInfo.Shape != nil {
+		rayHitMagnet = w.magnetOf(w.RayHitInfo.Shape)
+	}
+	w.Player.update(inp, &w.RayHitInfo, rayHitMagnet)
+	if inp.Gun != GunInputNone && w.RayHitInfo.Shape != nil {
+		w.applyGunForce(rayHitMagnet)
+		if enemy, ok := w.RayHitInfo.Shape.UserData.(*Enemy); ok && rayHitMagnet.metal {
+			enemy.pull()
+		}
+	}
+
+	for _, enemy := range w.Enemies {
+		if enemy.update(w) {
+			w.killEnemy(enemy)
+		}
+	}
+
+	for _, entity := range w.Entities {
+		entity.update(w)
+	}
+	w.checkPlayerInteraction(inp)
+}
+
+func (w *World) emit(kind EventKind, pos cp.Vector) {
+	w.Events = append(w.Events, Event{Kind: kind, Pos: pos})
+}
+
+func (w *World) killEnemy(e *Enemy) {
+	e.IsAlive = false
+	w.emit(EventEnemyKilled, e.Body.Position())
+
+	explodeSec := e.Archetype.ExplodeSec
+	w.scheduler.after(explodeSec, func() {
+		w.emit(EventEnemyExploded, e.Body.Position())
+		w.explode(e.Body.Position(), enemyBlastRadius, enemyBlastDamage, enemyBlastImpulse, e.Body, nil)
+	})
+
+	w.scheduler.after(explodeSec+enemyWreckSec, func() {
+		// Delete the enemy
+		// ----------------
+		w.space.RemoveShape(e.shape)
+		w.space.RemoveBody(e.Body)
+
+		// copy(w.Enemies[iEnemy:], w.Enemies[iEnemy+1:])
+		// w.Enemies[len(w.Enemies)-1] = nil
+		// w.Enemies = w.Enemies[:len(w.Enemies)-1]
+		// --
+
+		e.Removed = true
+	})
+}
+
+func (w *World) checkPlayerInteraction(inp *Input) {
+	if !inp.Activate {
+		return
+	}
+
+	interactionRadius := float64(interactionRadiusTile * TileLength)
+	for _, entity := range w.Entities {
+		if entity.Position().Distance(w.Player.Pos) < interactionRadius {
+			entity.interact(w)
+		}
+	}
+}
+
+func (w *World) rayCast() {
+	gunRay := w.Player.gunRay
+	w.RayHitInfo = w.space.SegmentQueryFirst(gunRay[0], gunRay[1], 0, filterGunRay)
+	var info cp.SegmentQueryInfo
+	var success bool
+
+	// Enemies look for the player and fire when they see it in their line of fire
+	for _, enemy := range w.Enemies {
+		if !enemy.IsAlive {
+			continue
+		}
+		enemy.look(w)
+		weapon := enemy.Archetype.Weapon
+		if weapon == nil {
+			continue
+		}
+		success = enemy.seesPlayer && w.Player.shape.SegmentQuery(enemy.fireRay[0], enemy.fireRay[1], weapon.Radius, &info)
+		if success && enemy.attackCooldownSec <= 0 {
+			rocketSpawnPosRelative := cp.Vector{X: weapon.Muzzle[0], Y: weapon.Muzzle[1]}
+			enemyPos := enemy.Body.Position()
+			enemyAngle := enemy.Body.Angle()
+			var rocketSpawnPos cp.Vector
+			var rocketAngle float64
+			if !enemy.TurnedLeft {
+				rocketSpawnPosHypot := math.Hypot(rocketSpawnPosRelative.X, rocketSpawnPosRelative.Y)
+				angleSpawnPos := math.Atan2(rocketSpawnPosRelative.Y, rocketSpawnPosRelative.X)
+				newSpawnPosAngle := angleSpawnPos + enemyAngle
+				rocketSpawnPos = enemyPos.Add(cp.Vector{
+					X: rocketSpawnPosHypot * math.Cos(newSpawnPosAngle), Y: rocketSpawnPosHypot * math.Sin(newSpawnPosAngle),
+				})
+				rocketAngle = enemyAngle
+			} else {
+				rocketSpawnPosRelativeLeft := cp.Vector{X: -rocketSpawnPosRelative.X, Y: rocketSpawnPosRelative.Y}
+
+				rocketSpawnPosHypot := math.Hypot(rocketSpawnPosRelativeLeft.X, rocketSpawnPosRelativeLeft.Y)
+				angleSpawnPos := math.Atan2(rocketSpawnPosRelativeLeft.Y, rocketSpawnPosRelativeLeft.X)
+				newSpawnPosAngle := angleSpawnPos + enemyAngle
+				rocketSpawnPos = enemyPos.Add(cp.Vector{
+					X: rocketSpawnPosHypot * math.Cos(newSpawnPosAngle), Y: rocketSpawnPosHypot * math.Sin(newSpawnPosAngle),
+				})
+				rocketAngle = enemyAngle - math.Pi
+			}
+			switch weapon.kind {
+			case RocketKindLaser:
+				laserAngle := w.Player.Pos.Sub(rocketSpawnPos).ToAngle() // the line of fire is wide, lasers aren't
+				w.fireLaser(rocketSpawnPos, laserAngle, weapon.Range, weapon.RocketDamage, enemy.group)
+			case RocketKindGrenade:
+				if enemy.TurnedLeft {
+					rocketAngle += grenadeLaunchAngle
+				} else {
+					rocketAngle -= grenadeLaunchAngle
+				}
+				fallthrough
+			default:
+				rocket := newRocket(rocketSpawnPos, rocketAngle, weapon.kind, weapon.RocketVelocity, weapon.RocketDamage, w.space, enemy.group)
+				w.addMagnet(rocket.shape, rocketKinds[weapon.kind].magnet)
+				w.rocketManager.rockets = append(w.rocketManager.rockets, rocket)
+			}
+			enemy.attackCooldownSec = float32(weapon.CooldownSec)
+		} else {
+			enemy.attackCooldownSec -= DeltaTimeSec
+		}
+		// fmt.Printf("success: %v\n", success)
+	}
+
+}
@@ -0,0 +1,295 @@
+// Copyright 2022 Anıl Konaç
+
+package world
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/anilkonac/magrix/asset"
+	"github.com/jakecoffman/cp"
+	"github.com/lafriks/go-tiled"
+)
+
+// loadWorld builds the world of an embedded map.
+func loadWorld(t *testing.T, path string) *World {
+	t.Helper()
+	gameMap, err := asset.LoadMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := New(gameMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+// loadTestWorld builds the world of a map made for a test.
+func loadTestWorld(t *testing.T, tmx string) *World {
+	t.Helper()
+	gameMap, err := tiled.LoadReader("", strings.NewReader(tmx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := New(gameMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+// testMap returns a 20x15 map with a floor at y=200, the player start at (40, 180), the button far away
+// and the objects added next to the button.
+func testMap(objects string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.8" orientation="orthogonal" renderorder="right-down" width="20" height="15" tilewidth="16" tileheight="16" infinite="0" nextlayerid="4" nextobjectid="100">
+ <objectgroup id="1" name="Walls">
+  <object id="1" x="0" y="200" width="320" height="40"/>
+ </objectgroup>
+ <objectgroup id="2" name="PlayerStart">
+  <object id="2" type="playerStart" x="40" y="180"><point/></object>
+ </objectgroup>
+ <objectgroup id="3" name="Objects">
+  <object id="3" type="button" x="300" y="16" width="16" height="16"/>
+` + objects + `
+ </objectgroup>
+</map>`
+}
+
+// withEnemies adds an enemies group with the objects to the map.
+func withEnemies(tmx, enemies string) string {
+	return strings.Replace(tmx, "</map>", ` <objectgroup id="4" name="Enemies">`+enemies+`
+ </objectgroup>
+</map>`, 1)
+}
+
+// stepWorld steps the world for the ticks with the same input.
+func stepWorld(w *World, inp Input, ticks int) {
+	for i := 0; i < ticks; i++ {
+		w.Step(&inp)
+	}
+}
+
+// ticks returns the number of steps lasting the seconds.
+func ticks(sec float64) int {
+	return int(math.Ceil(sec / DeltaTimeSec))
+}
+
+func TestStepShippedMaps(t *testing.T) {
+	for _, path := range []string{asset.Map, asset.MapTest} {
+		t.Run(path, func(t *testing.T) {
+			w := loadWorld(t, path)
+			stepWorld(w, Input{}, ticks(1)) // land
+			start := w.Player.Pos
+
+			// Walk right, then left
+			stepWorld(w, Input{Right: true}, ticks(0.5))
+			if w.Player.Pos.X <= start.X {
+				t.Errorf("walking right: x went from %.1f to %.1f", start.X, w.Player.Pos.X)
+			}
+			right := w.Player.Pos
+			stepWorld(w, Input{Left: true}, ticks(0.5))
+			if w.Player.Pos.X >= right.X {
+				t.Errorf("walking left: x went from %.1f to %.1f", right.X, w.Player.Pos.X)
+			}
+
+			// Jump
+			stepWorld(w, Input{}, ticks(1))
+			ground := w.Player.Pos.Y
+			stepWorld(w, Input{Up: true}, 1)
+			stepWorld(w, Input{}, ticks(0.2))
+			if w.Player.Pos.Y >= ground {
+				t.Errorf("jumping: y went from %.1f to %.1f", ground, w.Player.Pos.Y)
+			}
+
+			// Keep it running with scripted input
+			inp := Input{Gun: GunInputAttract}
+			for i := 0; i < ticks(20); i++ {
+				inp.Left = i%240 < 60
+				inp.Right = i%240 > 180
+				inp.Up = i%97 == 0
+				inp.Aim = w.Player.Pos.Add(cp.Vector{X: 100 * math.Cos(float64(i)/50), Y: 100 * math.Sin(float64(i)/50)})
+				w.Step(&inp)
+			}
+			if pos := w.Player.Pos; math.IsNaN(pos.X) || math.IsNaN(pos.Y) {
+				t.Fatalf("player position is %v", pos)
+			}
+		})
+	}
+}
+
+// terminalWithWall returns a terminal of the world and the electric wall it targets.
+func terminalWithWall(t *testing.T, w *World) (*Terminal, *ElectricWall) {
+	t.Helper()
+	for _, entity := range w.Entities {
+		terminal, ok := entity.(*Terminal)
+		if !ok {
+			continue
+		}
+		for _, id := range terminal.targets {
+			if wall, ok := w.entityByID[id].(*ElectricWall); ok {
+				return terminal, wall
+			}
+		}
+	}
+	t.Fatal("no terminal targeting an electric wall")
+	return nil, nil
+}
+
+func TestTerminalRemovesTargetWall(t *testing.T) {
+	w := loadWorld(t, asset.Map)
+	terminal, wall := terminalWithWall(t, w)
+
+	// Activate the terminal standing by it
+	w.Player.Body.SetPosition(terminal.Position())
+	stepWorld(w, Input{Activate: true}, 1)
+	if !terminal.Working {
+		t.Fatal("terminal isn't working after it is activated")
+	}
+
+	stepWorld(w, Input{}, ticks(terminal.delaySec)-2)
+	if wall.Removed {
+		t.Fatal("wall is removed before the delay of the terminal")
+	}
+	stepWorld(w, Input{}, 4) // rounding errors of the clock may take a tick
+	if !wall.Removed {
+		t.Fatal("wall isn't removed after the delay of the terminal")
+	}
+	if w.space.ContainsShape(wall.shapes[0]) {
+		t.Fatal("shape of the removed wall is still in the space")
+	}
+}
+
+// removeGroup removes the object group with the name from the map.
+func removeGroup(gameMap *tiled.Map, name string) {
+	groups := gameMap.ObjectGroups[:0]
+	for _, group := range gameMap.ObjectGroups {
+		if group.Name != name {
+			groups = append(groups, group)
+		}
+	}
+	gameMap.ObjectGroups = groups
+}
+
+// removeObjects removes the objects of the type from the map.
+func removeObjects(gameMap *tiled.Map, typ string) {
+	for _, group := range gameMap.ObjectGroups {
+		objects := group.Objects[:0]
+		for _, obj := range group.Objects {
+			if objectType(obj) != typ {
+				objects = append(objects, obj)
+			}
+		}
+		group.Objects = objects
+	}
+}
+
+// generateWalls sets the generateWalls property of the map.
+func generateWalls(gameMap *tiled.Map, generate bool) {
+	if gameMap.Properties == nil {
+		gameMap.Properties = &tiled.Properties{}
+	}
+	*gameMap.Properties = append(*gameMap.Properties,
+		&tiled.Property{Name: propertyGenerateWalls, Type: "bool", Value: strconv.FormatBool(generate)})
+}
+
+func TestLoadMapMissingParts(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		remove  func(gameMap *tiled.Map)
+		wantErr string // part of the error
+	}{
+		{"walls group", func(m *tiled.Map) { removeGroup(m, groupNameWalls) }, groupNameWalls},
+		{"walls group and generateWalls", func(m *tiled.Map) {
+			removeGroup(m, groupNameWalls)
+			generateWalls(m, false)
+		}, groupNameWalls},
+		{"platforms layer to generate walls", func(m *tiled.Map) {
+			generateWalls(m, true)
+			var layers []*tiled.Layer
+			for _, layer := range m.Layers {
+				if layer.Name != LayerNamePlatforms {
+					layers = append(layers, layer)
+				}
+			}
+			m.Layers = layers
+		}, LayerNamePlatforms},
+		{"player start group", func(m *tiled.Map) { removeGroup(m, groupNamePlayerStart) }, groupNamePlayerStart},
+		{"player start", func(m *tiled.Map) { removeObjects(m, objectTypePlayerStart) }, objectTypePlayerStart},
+		{"button", func(m *tiled.Map) { removeObjects(m, ObjectTypeButton) }, ObjectTypeButton},
+	} {
+		gameMap, err := asset.LoadMap(asset.Map)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tc.remove(gameMap)
+		_, err = New(gameMap)
+		if err == nil {
+			t.Errorf("%s missing: no error", tc.name)
+		} else if !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s missing: error %q doesn't mention %q", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+// Replays only record the input, so stepping the same map with the same input must end up in the same state.
+func TestStepIsDeterministic(t *testing.T) {
+	run := func() *World {
+		w := loadWorld(t, asset.MapTest)
+		inp := Input{Gun: GunInputAttract}
+		for i := 0; i < ticks(15); i++ {
+			inp.Left = i%240 < 60
+			inp.Right = i%240 > 180
+			inp.Up = i%97 == 0
+			inp.Activate = i%150 == 0
+			inp.Aim = w.Player.Pos.Add(cp.Vector{X: 100 * math.Cos(float64(i)/40), Y: 80})
+			w.Step(&inp)
+		}
+		return w
+	}
+
+	w1, w2 := run(), run()
+	if w1.Player.Pos != w2.Player.Pos || w1.Player.NumLives != w2.Player.NumLives {
+		t.Fatalf("players differ: %v %d lives, %v %d lives", w1.Player.Pos, w1.Player.NumLives, w2.Player.Pos, w2.Player.NumLives)
+	}
+	for iEnemy, enemy := range w1.Enemies {
+		other := w2.Enemies[iEnemy]
+		if enemy.Body.Position() != other.Body.Position() || enemy.IsAlive != other.IsAlive {
+			t.Fatalf("enemy %d differs: %v, %v", iEnemy, enemy.Body.Position(), other.Body.Position())
+		}
+	}
+	if len(w1.Rockets()) != len(w2.Rockets()) {
+		t.Fatalf("%d rockets, %d rockets", len(w1.Rockets()), len(w2.Rockets()))
+	}
+}
+
+func TestGunRay(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		objects string
+		hitsBox bool
+	}{
+		{"nothing in between", "", true},
+		{"electric wall in between", `
+  <object id="10" type="electricWall" x="100" y="100" width="8" height="100"/>`, false},
+		{"sensor in between", `
+  <object id="10" type="checkpoint" x="100" y="150" width="32" height="50"/>`, true},
+	} {
+		w := loadTestWorld(t, testMap(tc.objects))
+		stepWorld(w, Input{}, ticks(0.5)) // land
+		box := launchModule(w, cp.Vector{X: 200, Y: 200 - TileLength/2}, 0)
+		stepWorld(w, Input{}, 1)
+
+		posBox := box.Position()
+		stepWorld(w, Input{Aim: posBox, Gun: GunInputAttract}, ticks(0.5))
+		if hitsBox := w.RayHitInfo.Shape != nil && w.RayHitInfo.Shape.Body() == box; hitsBox != tc.hitsBox {
+			t.Errorf("%s: ray hits the box is %v", tc.name, hitsBox)
+		}
+		if pulled := box.Position().X < posBox.X-1; pulled != tc.hitsBox {
+			t.Errorf("%s: box pulled is %v, from %v to %v", tc.name, pulled, posBox, box.Position())
+		}
+	}
+}